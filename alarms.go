@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"time"
@@ -56,22 +57,21 @@ type Alarm struct {
 
 // GetAlarms returns Alarms for a list of Sites.
 func (u *Unifi) GetAlarms(sites []*Site) ([]*Alarm, error) {
-	data := []*Alarm{}
-
-	for _, site := range sites {
-		response, err := u.GetAlarmsSite(site)
-		if err != nil {
-			return data, err
-		}
-
-		data = append(data, response...)
-	}
+	return u.GetAlarmsCtx(context.Background(), sites)
+}
 
-	return data, nil
+// GetAlarmsCtx is the same as GetAlarms, but uses the provided context.
+func (u *Unifi) GetAlarmsCtx(ctx context.Context, sites []*Site) ([]*Alarm, error) {
+	return eachSite(ctx, u, sites, u.GetAlarmsSiteCtx)
 }
 
 // GetAlarmsSite retreives the Alarms for a single Site.
 func (u *Unifi) GetAlarmsSite(site *Site) ([]*Alarm, error) {
+	return u.GetAlarmsSiteCtx(context.Background(), site)
+}
+
+// GetAlarmsSiteCtx is the same as GetAlarmsSite, but uses the provided context.
+func (u *Unifi) GetAlarmsSiteCtx(ctx context.Context, site *Site) ([]*Alarm, error) {
 	if site == nil || site.Name == "" {
 		return nil, ErrNoSiteProvided
 	}
@@ -85,7 +85,7 @@ func (u *Unifi) GetAlarmsSite(site *Site) ([]*Alarm, error) {
 		}
 	)
 
-	if err := u.GetData(path, &alarms, ""); err != nil {
+	if err := u.GetDataCtx(ctx, path, &alarms, ""); err != nil {
 		return alarms.Data, err
 	}
 
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
@@ -27,22 +28,23 @@ type Anomaly struct {
 
 // GetAnomalies returns Anomalies for a list of Sites.
 func (u *Unifi) GetAnomalies(sites []*Site, timeRange ...time.Time) ([]*Anomaly, error) {
-	data := []*Anomaly{}
-
-	for _, site := range sites {
-		response, err := u.GetAnomaliesSite(site, timeRange...)
-		if err != nil {
-			return data, err
-		}
-
-		data = append(data, response...)
-	}
+	return u.GetAnomaliesCtx(context.Background(), sites, timeRange...)
+}
 
-	return data, nil
+// GetAnomaliesCtx is the same as GetAnomalies, but uses the provided context.
+func (u *Unifi) GetAnomaliesCtx(ctx context.Context, sites []*Site, timeRange ...time.Time) ([]*Anomaly, error) {
+	return eachSite(ctx, u, sites, func(ctx context.Context, site *Site) ([]*Anomaly, error) {
+		return u.GetAnomaliesSiteCtx(ctx, site, timeRange...)
+	})
 }
 
 // GetAnomaliesSite retreives the Anomalies for a single Site.
 func (u *Unifi) GetAnomaliesSite(site *Site, timeRange ...time.Time) ([]*Anomaly, error) {
+	return u.GetAnomaliesSiteCtx(context.Background(), site, timeRange...)
+}
+
+// GetAnomaliesSiteCtx is the same as GetAnomaliesSite, but uses the provided context.
+func (u *Unifi) GetAnomaliesSiteCtx(ctx context.Context, site *Site, timeRange ...time.Time) ([]*Anomaly, error) {
 	if site == nil || site.Name == "" {
 		return nil, ErrNoSiteProvided
 	}
@@ -59,7 +61,7 @@ func (u *Unifi) GetAnomaliesSite(site *Site, timeRange ...time.Time) ([]*Anomaly
 
 	if params, err := makeAnomalyParams("hourly", timeRange...); err != nil {
 		return anomalies, err
-	} else if err := u.GetData(path+params, &data, ""); err != nil {
+	} else if err := u.GetDataCtx(ctx, path+params, &data, ""); err != nil {
 		return anomalies, err
 	}
 
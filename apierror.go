@@ -0,0 +1,131 @@
+package unifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// These errors may be matched with errors.Is against an error returned by any
+// request method. They are matched by the HTTP status code, or by the message
+// the controller returns in meta.msg.
+var (
+	ErrNotFound     = fmt.Errorf("not found")
+	ErrInvalidLogin = fmt.Errorf("invalid login")
+	ErrNoPermission = fmt.Errorf("no permission")
+	ErrRateLimited  = fmt.Errorf("rate limited")
+)
+
+// maxErrorBody is how much of a response body is kept in an APIError.
+const maxErrorBody = 512
+
+// APIError is returned when the controller answers with a non-200 status code,
+// or with a 200 and an error in the meta block: {"meta":{"rc":"error","msg":"..."}}.
+// Use errors.As to get one. An APIError with a non-200 status wraps ErrInvalidStatusCode.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	RC         string // meta.rc from the response, usually "error".
+	Msg        string // meta.msg from the response, like api.err.NoSiteContext.
+	Body       string // the start of the response body.
+}
+
+// Error satisfies the error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Msg != "" {
+		msg += " (" + e.Msg + ")"
+	}
+
+	if e.StatusCode != http.StatusOK {
+		msg += ": " + ErrInvalidStatusCode.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns ErrInvalidStatusCode when the status code was not 200.
+func (e *APIError) Unwrap() error {
+	if e.StatusCode != http.StatusOK {
+		return ErrInvalidStatusCode
+	}
+
+	return nil
+}
+
+// Is allows matching an APIError to ErrNotFound, ErrInvalidLogin, ErrNoPermission
+// and ErrRateLimited with errors.Is.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound ||
+			e.msgIs("api.err.NoSiteContext", "api.err.NotFound", "api.err.IdInvalid", "api.err.ObjectNotFound")
+	case ErrInvalidLogin:
+		return e.StatusCode == http.StatusUnauthorized ||
+			e.msgIs("api.err.Invalid", "api.err.LoginRequired", "AUTHENTICATION_FAILED_INVALID_CREDENTIALS")
+	case ErrNoPermission:
+		return e.StatusCode == http.StatusForbidden || e.msgIs("api.err.NoPermission")
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests ||
+			e.msgIs("api.err.TooManyRequests", "AUTHENTICATION_FAILED_LIMIT_REACHED")
+	default:
+		return false
+	}
+}
+
+func (e *APIError) msgIs(msgs ...string) bool {
+	for _, msg := range msgs {
+		if e.Msg == msg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rcError finds a meta.rc error in a response body, with or without spaces around the colon.
+var rcError = regexp.MustCompile(`"rc"\s*:\s*"error"`)
+
+// apiError returns an APIError if the response is an error, otherwise nil.
+// A 200 response is only parsed when the meta block near the start of the body
+// reports an error, so large payloads are not unmarshalled twice.
+func apiError(req *http.Request, status int, body []byte) error {
+	head := body
+	if len(head) > maxErrorBody {
+		head = head[:maxErrorBody]
+	}
+
+	if status == http.StatusOK && !rcError.Match(head) {
+		return nil
+	}
+
+	e := &APIError{
+		StatusCode: status,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Body:       strings.TrimSpace(string(head)),
+	}
+
+	var response struct {
+		Meta struct {
+			RC  string `json:"rc"`
+			Msg string `json:"msg"`
+		} `json:"meta"`
+		Code    string `json:"code"`    // UniFi OS
+		Message string `json:"message"` // UniFi OS
+	}
+
+	if json.Unmarshal(body, &response) == nil {
+		e.RC = response.Meta.RC
+		e.Msg = pick(response.Meta.Msg, response.Code, response.Message)
+	}
+
+	if status == http.StatusOK && e.RC != "error" {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,55 @@
+package unifi // nolint: testpackage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/s/gone/stat/sta":
+			_, _ = w.Write([]byte(`{"meta":{"rc":"error","msg":"api.err.NoSiteContext"},"data":[]}`))
+		case "/api/s/locked/stat/sta":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"meta":{"rc":"error","msg":"api.err.NoPermission"},"data":[]}`))
+		case "/api/s/pretty/stat/sta":
+			_, _ = w.Write([]byte("{\n  \"meta\": {\n    \"rc\" : \"error\",\n    \"msg\": \"api.err.NoSiteContext\"\n  }\n}"))
+		case "/api/s/busy/stat/sta":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(`{"meta":{"rc":"ok"},"data":[{"msg":"\"rc\":\"error\""}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	u := newUnifi(&Config{URL: srv.URL}, nil)
+
+	var apiErr *APIError
+
+	err := u.GetData("/api/s/gone/stat/sta", &struct{}{})
+	a.True(errors.As(err, &apiErr), "a meta error must be returned as an APIError")
+	a.Equal(http.StatusOK, apiErr.StatusCode)
+	a.Equal("error", apiErr.RC)
+	a.Equal("api.err.NoSiteContext", apiErr.Msg)
+	a.Equal(http.MethodGet, apiErr.Method)
+	a.Equal("/api/s/gone/stat/sta", apiErr.Path)
+	a.ErrorIs(err, ErrNotFound)
+	a.NotErrorIs(err, ErrInvalidStatusCode, "a 200 is not an invalid status code")
+
+	err = u.GetData("/api/s/locked/stat/sta", &struct{}{})
+	a.ErrorIs(err, ErrNoPermission)
+	a.ErrorIs(err, ErrInvalidStatusCode, "a non-200 must still match ErrInvalidStatusCode")
+	a.NotErrorIs(err, ErrNotFound)
+
+	a.ErrorIs(u.GetData("/api/s/pretty/stat/sta", &struct{}{}), ErrNotFound, "spaced bodies must be parsed too")
+	a.ErrorIs(u.GetData("/api/s/busy/stat/sta", &struct{}{}), ErrRateLimited)
+	a.NoError(u.GetData("/api/s/ok/stat/sta", &struct{}{}), "an error string in the data must be ignored")
+}
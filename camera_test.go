@@ -0,0 +1,91 @@
+package unifi // nolint: testpackage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed examples/camera.json
+var cameraSample []byte
+
+func TestFlexTime(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var r struct {
+		Number FlexTime `json:"number"`
+		String FlexTime `json:"string"`
+		Zero   FlexTime `json:"zero"`
+		Nil    FlexTime `json:"nil"`
+	}
+
+	a.NoError(json.Unmarshal([]byte(`{"number": 1698765432100, "string": "1698765432100", "zero": 0, "nil": null}`), &r))
+	a.Equal(time.UnixMilli(1698765432100), r.Number.Val)
+	a.Equal("1698765432100", r.Number.Txt)
+	a.Equal(r.Number.Val, r.String.Val)
+	a.True(r.Zero.Val.IsZero())
+	a.True(r.Nil.Val.IsZero())
+	a.Error(json.Unmarshal([]byte(`{"number": {}}`), &r), "a non-string and non-number must produce an error.")
+
+	out, err := json.Marshal(r)
+	a.NoError(err)
+	a.JSONEq(`{"number": 1698765432100, "string": 1698765432100, "zero": null, "nil": null}`, string(out))
+}
+
+func TestCameraDecode(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var bootstrap Bootstrap
+
+	require.NoError(t, json.Unmarshal(bootstrapSample, &bootstrap))
+	require.Len(t, bootstrap.Cameras, 1)
+
+	doorbell := bootstrap.Cameras[0]
+	a.Equal(time.UnixMilli(1698765432100), doorbell.UpSince.Val)
+	a.Equal(time.UnixMilli(1699999990000), doorbell.LastMotion.Val)
+	a.Equal(time.UnixMilli(1699999999000), doorbell.LastSeen.Val)
+	a.Equal(time.UnixMilli(1697000000000), doorbell.Stats.Video.RecordingStart.Val)
+	a.Equal(time.UnixMilli(1699999999000), doorbell.Stats.Video.RecordingEnd.Val)
+	a.True(doorbell.FeatureFlags.IsDoorbell)
+	a.Equal([]SmartDetectType{SmartDetectPerson, SmartDetectVehicle, SmartDetectPackage},
+		doorbell.FeatureFlags.SmartDetectTypes)
+	a.Equal([]SmartDetectAudioType{SmartDetectAudioSmokeCmonx}, doorbell.FeatureFlags.SmartDetectAudioTypes)
+	require.Len(t, doorbell.MotionZones, 1)
+	a.Equal([]ZonePoint{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, doorbell.MotionZones[0].Points)
+	a.Equal(50, doorbell.MotionZones[0].Sensitivity)
+	require.Len(t, doorbell.SmartDetectZones, 1)
+	a.True(doorbell.SmartDetectZones[0].IsTriggerLightEnabled)
+	a.Equal([]SmartDetectType{SmartDetectPerson, SmartDetectPackage}, doorbell.SmartDetectZones[0].ObjectTypes)
+	a.Zero(doorbell.FeatureFlags.Pan.Steps.Max.Val, "a null range must decode to zero.")
+
+	var ptz Camera
+
+	require.NoError(t, json.Unmarshal(cameraSample, &ptz))
+	a.Equal(time.UnixMilli(1698765432100), ptz.UpSince.Val, "a string timestamp must decode.")
+	a.True(ptz.LastMotion.Val.IsZero(), "a null timestamp must be the zero time.")
+	a.EqualValues(1000, ptz.PhyRate.Val)
+	a.EqualValues(1234567, ptz.Uptime.Val)
+	a.True(ptz.FeatureFlags.IsPtz)
+	a.EqualValues(36000, ptz.FeatureFlags.Pan.Steps.Max.Val)
+	a.EqualValues(10, ptz.FeatureFlags.Pan.Steps.Step.Val)
+	a.EqualValues(-2000, ptz.FeatureFlags.Tilt.Steps.Min.Val)
+	a.EqualValues(-20, ptz.FeatureFlags.Tilt.Degrees.Min.Val)
+	a.EqualValues(2010, ptz.FeatureFlags.Zoom.Steps.Max.Val)
+	a.Equal([]FlexInt{{Val: 30, Txt: "30"}, {Val: 25, Txt: "25"}}, ptz.FeatureFlags.VideoModeMaxFps)
+	a.Contains(ptz.FeatureFlags.SmartDetectTypes, SmartDetectLicensePlate)
+	a.Equal([]SmartDetectAudioType{SmartDetectAudioSmoke, SmartDetectAudioSiren}, ptz.SmartDetectSettings.AudioTypes)
+	a.Equal([]SmartDetectType{SmartDetectPerson, SmartDetectVehicle, SmartDetectLicensePlate},
+		ptz.SmartDetectSettings.ObjectTypes)
+	require.Len(t, ptz.PrivacyZones, 1)
+	a.Equal("Neighbour", ptz.PrivacyZones[0].Name)
+	a.Equal(ZonePoint{0.05, 0.1}, ptz.PrivacyZones[0].Points[0])
+	require.Len(t, ptz.SmartDetectLines, 1)
+	a.Equal([]ZonePoint{{0.12, 0.8}, {0.88, 0.74}}, ptz.SmartDetectLines[0].Points)
+	a.Equal([]SmartDetectType{SmartDetectVehicle}, ptz.SmartDetectLines[0].ObjectTypes)
+}
@@ -0,0 +1,266 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// These are returned when a camera update is rejected, or is not applied by the NVR.
+var (
+	ErrInvalidCameraPatch = fmt.Errorf("invalid camera update")
+	ErrCameraNotUpdated   = fmt.Errorf("camera did not apply the update")
+)
+
+// RecordingMode is when a camera records.
+type RecordingMode string
+
+// These are the camera recording modes.
+const (
+	RecordingModeAlways     RecordingMode = "always"
+	RecordingModeNever      RecordingMode = "never"
+	RecordingModeSchedule   RecordingMode = "schedule"
+	RecordingModeDetections RecordingMode = "detections"
+)
+
+// IRLEDMode is when a camera turns on its infrared LEDs.
+type IRLEDMode string
+
+// These are the camera infrared LED modes.
+const (
+	IRLEDModeAuto           IRLEDMode = "auto"
+	IRLEDModeOn             IRLEDMode = "on"
+	IRLEDModeOff            IRLEDMode = "off"
+	IRLEDModeAutoFilterOnly IRLEDMode = "autoFilterOnly"
+	IRLEDModeCustom         IRLEDMode = "custom"
+)
+
+// PrivacyZoneName is the name of the full frame privacy zone added by CameraPatch.SetPrivacyMode.
+const PrivacyZoneName = "privacy mode"
+
+// CameraPatch is a partial camera update for UpdateCamera. Only the fields that are
+// set are sent; nil fields are not changed on the camera. Use the Set methods to
+// build one, or set the fields directly.
+type CameraPatch struct {
+	Name              *string               `json:"name,omitempty"`
+	IsMicEnabled      *bool                 `json:"isMicEnabled,omitempty"`
+	MicVolume         *int                  `json:"micVolume,omitempty"`
+	RecordingSettings *CameraRecordingPatch `json:"recordingSettings,omitempty"`
+	IspSettings       *CameraISPPatch       `json:"ispSettings,omitempty"`
+	LedSettings       *CameraLEDPatch       `json:"ledSettings,omitempty"`
+	OsdSettings       *CameraOSDPatch       `json:"osdSettings,omitempty"`
+	SpeakerSettings   *CameraSpeakerPatch   `json:"speakerSettings,omitempty"`
+	PrivacyZones      *[]CameraZone         `json:"privacyZones,omitempty"`
+}
+
+// CameraRecordingPatch changes a camera's recording settings.
+type CameraRecordingPatch struct {
+	Mode                  *RecordingMode `json:"mode,omitempty"`
+	PrePaddingSecs        *int           `json:"prePaddingSecs,omitempty"`
+	PostPaddingSecs       *int           `json:"postPaddingSecs,omitempty"`
+	EnableMotionDetection *bool          `json:"enableMotionDetection,omitempty"`
+}
+
+// CameraISPPatch changes a camera's image settings.
+type CameraISPPatch struct {
+	IrLedMode  *IRLEDMode `json:"irLedMode,omitempty"`
+	IrLedLevel *int       `json:"irLedLevel,omitempty"`
+}
+
+// CameraLEDPatch changes a camera's status light.
+type CameraLEDPatch struct {
+	IsEnabled *bool `json:"isEnabled,omitempty"`
+}
+
+// CameraOSDPatch changes what a camera draws on its video.
+type CameraOSDPatch struct {
+	IsNameEnabled  *bool `json:"isNameEnabled,omitempty"`
+	IsDateEnabled  *bool `json:"isDateEnabled,omitempty"`
+	IsLogoEnabled  *bool `json:"isLogoEnabled,omitempty"`
+	IsDebugEnabled *bool `json:"isDebugEnabled,omitempty"`
+}
+
+// CameraSpeakerPatch changes a camera's speaker settings.
+type CameraSpeakerPatch struct {
+	IsEnabled *bool `json:"isEnabled,omitempty"`
+	Volume    *int  `json:"volume,omitempty"`
+}
+
+// SetRecordingMode sets when the camera records.
+func (p *CameraPatch) SetRecordingMode(mode RecordingMode) *CameraPatch {
+	if p.RecordingSettings == nil {
+		p.RecordingSettings = &CameraRecordingPatch{}
+	}
+
+	p.RecordingSettings.Mode = &mode
+
+	return p
+}
+
+// SetIRLEDMode sets when the camera turns on its infrared LEDs.
+func (p *CameraPatch) SetIRLEDMode(mode IRLEDMode) *CameraPatch {
+	if p.IspSettings == nil {
+		p.IspSettings = &CameraISPPatch{}
+	}
+
+	p.IspSettings.IrLedMode = &mode
+
+	return p
+}
+
+// SetStatusLED turns the camera's status light on or off.
+func (p *CameraPatch) SetStatusLED(enabled bool) *CameraPatch {
+	p.LedSettings = &CameraLEDPatch{IsEnabled: &enabled}
+	return p
+}
+
+// SetMicVolume sets the camera's microphone volume, from 0 to 100.
+func (p *CameraPatch) SetMicVolume(volume int) *CameraPatch {
+	p.MicVolume = &volume
+	return p
+}
+
+// SetOSD sets which details the camera draws on its video.
+func (p *CameraPatch) SetOSD(name, date, logo bool) *CameraPatch {
+	if p.OsdSettings == nil {
+		p.OsdSettings = &CameraOSDPatch{}
+	}
+
+	p.OsdSettings.IsNameEnabled = &name
+	p.OsdSettings.IsDateEnabled = &date
+	p.OsdSettings.IsLogoEnabled = &logo
+
+	return p
+}
+
+// SetPrivacyMode hides the whole picture with a privacy zone, mutes the microphone
+// and stops recording. The camera's current privacy zones are kept. Disabling privacy
+// mode only removes the privacy zone; set the mic volume and recording mode to restore them.
+func (p *CameraPatch) SetPrivacyMode(camera *Camera, enabled bool) *CameraPatch {
+	zones := []CameraZone{}
+	nextID := 0
+
+	for _, zone := range camera.PrivacyZones {
+		if zone.Name != PrivacyZoneName {
+			zones = append(zones, zone)
+		}
+
+		nextID = max(nextID, zone.ID+1)
+	}
+
+	if enabled {
+		zones = append(zones, CameraZone{
+			ID:     nextID,
+			Name:   PrivacyZoneName,
+			Color:  "#85BCEC",
+			Points: []ZonePoint{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		})
+
+		p.SetMicVolume(0).SetRecordingMode(RecordingModeNever)
+	}
+
+	p.PrivacyZones = &zones
+
+	return p
+}
+
+// validate checks the values that the NVR would reject.
+func (p *CameraPatch) validate() error {
+	check := func(name string, value *int, max int) error {
+		if value != nil && (*value < 0 || *value > max) {
+			return fmt.Errorf("%w: %s must be from 0 to %d, not %d", ErrInvalidCameraPatch, name, max, *value)
+		}
+
+		return nil
+	}
+
+	if err := check("mic volume", p.MicVolume, 100); err != nil { //nolint:gomnd
+		return err
+	}
+
+	if p.SpeakerSettings != nil {
+		if err := check("speaker volume", p.SpeakerSettings.Volume, 100); err != nil { //nolint:gomnd
+			return err
+		}
+	}
+
+	if p.IspSettings != nil {
+		if err := check("IR LED level", p.IspSettings.IrLedLevel, 255); err != nil { //nolint:gomnd
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateCamera changes the camera settings provided in the patch, and returns the
+// updated camera. An error wrapping ErrCameraNotUpdated is returned if the camera
+// returned by the NVR does not have the new settings.
+func (u *Unifi) UpdateCamera(cameraID string, patch *CameraPatch) (*Camera, error) {
+	return u.UpdateCameraCtx(context.Background(), cameraID, patch)
+}
+
+// UpdateCameraCtx is the same as UpdateCamera, but uses the provided context.
+func (u *Unifi) UpdateCameraCtx(ctx context.Context, cameraID string, patch *CameraPatch) (*Camera, error) {
+	if patch == nil {
+		return nil, fmt.Errorf("%w: no changes", ErrInvalidCameraPatch)
+	}
+
+	if err := patch.validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	if string(data) == "{}" {
+		return nil, fmt.Errorf("%w: no changes", ErrInvalidCameraPatch)
+	}
+
+	var camera Camera
+
+	if err := u.PatchDataCtx(ctx, fmt.Sprintf(APIProtectCameraPath, cameraID), &camera, string(data)); err != nil {
+		return nil, err
+	}
+
+	if camera.ID != cameraID {
+		return nil, fmt.Errorf("%w: returned camera id %q", ErrCameraNotUpdated, camera.ID)
+	}
+
+	if fields := unappliedFields(data, &camera); len(fields) > 0 {
+		return &camera, fmt.Errorf("%w: %s", ErrCameraNotUpdated, strings.Join(fields, ", "))
+	}
+
+	return &camera, nil
+}
+
+// unappliedFields returns the fields in a patch that have a different value in the object.
+func unappliedFields(patch []byte, object interface{}) []string {
+	var want, have map[string]interface{}
+
+	current, _ := json.Marshal(object)
+	_ = json.Unmarshal(patch, &want)
+	_ = json.Unmarshal(current, &have)
+
+	fields := []string{}
+	compareFields(&fields, "", want, have)
+	sort.Strings(fields)
+
+	return fields
+}
+
+func compareFields(fields *[]string, prefix string, want, have map[string]interface{}) {
+	for key, value := range want {
+		if nested, ok := value.(map[string]interface{}); ok {
+			current, _ := have[key].(map[string]interface{})
+			compareFields(fields, prefix+key+".", nested, current)
+		} else if !reflect.DeepEqual(value, have[key]) {
+			*fields = append(*fields, prefix+key)
+		}
+	}
+}
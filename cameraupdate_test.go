@@ -0,0 +1,91 @@
+package unifi // nolint: testpackage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCamera(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	const id = "65a1c2d30188f903e7001a22"
+
+	bodies := make(chan string, 1)
+	ignore := false // the NVR ignores the recording mode when true.
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != APIPrefixNew+"/api/cameras/"+id {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+
+		var camera, changes map[string]interface{}
+
+		_ = json.Unmarshal(cameraSample, &camera)
+		_ = json.Unmarshal(body, &changes)
+
+		if ignore {
+			delete(changes, "recordingSettings")
+		}
+
+		mergeJSON(camera, changes)
+		_ = json.NewEncoder(w).Encode(camera)
+	})
+
+	patch := (&CameraPatch{}).SetRecordingMode(RecordingModeDetections).SetIRLEDMode(IRLEDModeOff).SetStatusLED(false)
+	camera, err := u.UpdateCamera(id, patch)
+	require.NoError(t, err)
+	a.JSONEq(`{"recordingSettings":{"mode":"detections"},"ispSettings":{"irLedMode":"off"},`+
+		`"ledSettings":{"isEnabled":false}}`, <-bodies, "only the changed fields may be sent.")
+	a.Equal("detections", camera.RecordingSettings.Mode)
+	a.False(camera.LedSettings.IsEnabled)
+
+	var current Camera
+
+	require.NoError(t, json.Unmarshal(cameraSample, &current))
+
+	camera, err = u.UpdateCamera(id, (&CameraPatch{}).SetPrivacyMode(&current, true))
+	require.NoError(t, err)
+	<-bodies
+	require.Len(t, camera.PrivacyZones, 2, "existing privacy zones must be kept.")
+	a.Equal(PrivacyZoneName, camera.PrivacyZones[1].Name)
+	a.Equal(3, camera.PrivacyZones[1].ID)
+	a.Zero(camera.MicVolume)
+	a.Equal("never", camera.RecordingSettings.Mode)
+
+	camera, err = u.UpdateCamera(id, (&CameraPatch{}).SetPrivacyMode(camera, false))
+	require.NoError(t, err)
+	a.JSONEq(`{"privacyZones":[`+mustJSON(t, current.PrivacyZones[0])+`]}`, <-bodies)
+	a.Len(camera.PrivacyZones, 1)
+
+	ignore = true
+	camera, err = u.UpdateCamera(id, (&CameraPatch{}).SetRecordingMode(RecordingModeNever).SetMicVolume(10))
+	<-bodies
+	a.ErrorIs(err, ErrCameraNotUpdated)
+	a.ErrorContains(err, "recordingSettings.mode")
+	a.NotContains(err.Error(), "micVolume")
+	a.NotNil(camera, "the returned camera must be returned with the error.")
+
+	_, err = u.UpdateCamera(id, (&CameraPatch{}).SetMicVolume(101))
+	a.ErrorIs(err, ErrInvalidCameraPatch)
+	_, err = u.UpdateCamera(id, &CameraPatch{})
+	a.ErrorIs(err, ErrInvalidCameraPatch)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(data)
+}
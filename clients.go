@@ -1,66 +1,74 @@
 package unifi
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
 // GetClients returns a response full of clients' data from the UniFi Controller.
 func (u *Unifi) GetClients(sites []*Site) ([]*Client, error) {
-	data := make([]*Client, 0)
-
-	for _, site := range sites {
-		var response struct {
-			Data []*Client `json:"data"`
-		}
-
-		u.DebugLog("Polling Controller, retreiving UniFi Clients, site %s ", site.SiteName)
-
-		clientPath := fmt.Sprintf(APIClientPath, site.Name)
-		if err := u.GetData(clientPath, &response); err != nil {
-			return nil, err
-		}
-
-		for i, d := range response.Data {
-			// Add special SourceName value.
-			response.Data[i].SourceName = u.URL
-			// Add the special "Site Name" to each client. This becomes a Grafana filter somewhere.
-			response.Data[i].SiteName = site.SiteName
-			// Fix name and hostname fields. Sometimes one or the other is blank.
-			response.Data[i].Hostname = strings.TrimSpace(pick(d.Hostname, d.Name, d.Mac))
-			response.Data[i].Name = strings.TrimSpace(pick(d.Name, d.Hostname))
-		}
-
-		data = append(data, response.Data...)
+	return u.GetClientsCtx(context.Background(), sites)
+}
+
+// GetClientsCtx is the same as GetClients, but uses the provided context.
+func (u *Unifi) GetClientsCtx(ctx context.Context, sites []*Site) ([]*Client, error) {
+	return eachSite(ctx, u, sites, u.getClientsSite)
+}
+
+func (u *Unifi) getClientsSite(ctx context.Context, site *Site) ([]*Client, error) {
+	var response struct {
+		Data []*Client `json:"data"`
+	}
+
+	u.DebugLog("Polling Controller, retreiving UniFi Clients, site %s ", site.SiteName)
+
+	clientPath := fmt.Sprintf(APIClientPath, site.Name)
+	if err := u.GetDataCtx(ctx, clientPath, &response); err != nil {
+		return nil, err
 	}
 
-	return data, nil
+	for i, d := range response.Data {
+		// Add special SourceName value.
+		response.Data[i].SourceName = u.URL
+		// Add the special "Site Name" to each client. This becomes a Grafana filter somewhere.
+		response.Data[i].SiteName = site.SiteName
+		// Fix name and hostname fields. Sometimes one or the other is blank.
+		response.Data[i].Hostname = strings.TrimSpace(pick(d.Hostname, d.Name, d.Mac))
+		response.Data[i].Name = strings.TrimSpace(pick(d.Name, d.Hostname))
+	}
+
+	return response.Data, nil
 }
 
 // GetClientsDPI garners dpi data for clients.
 func (u *Unifi) GetClientsDPI(sites []*Site) ([]*DPITable, error) {
-	var data []*DPITable
+	return u.GetClientsDPICtx(context.Background(), sites)
+}
 
-	for _, site := range sites {
-		u.DebugLog("Polling Controller, retreiving Client DPI data, site %s", site.SiteName)
+// GetClientsDPICtx is the same as GetClientsDPI, but uses the provided context.
+func (u *Unifi) GetClientsDPICtx(ctx context.Context, sites []*Site) ([]*DPITable, error) {
+	return eachSite(ctx, u, sites, u.getClientsDPISite)
+}
 
-		var response struct {
-			Data []*DPITable `json:"data"`
-		}
+func (u *Unifi) getClientsDPISite(ctx context.Context, site *Site) ([]*DPITable, error) {
+	u.DebugLog("Polling Controller, retreiving Client DPI data, site %s", site.SiteName)
 
-		clientDPIpath := fmt.Sprintf(APIClientDPI, site.Name)
-		if err := u.GetData(clientDPIpath, &response, `{"type":"by_app"}`); err != nil {
-			return nil, err
-		}
+	var response struct {
+		Data []*DPITable `json:"data"`
+	}
+
+	clientDPIpath := fmt.Sprintf(APIClientDPI, site.Name)
+	if err := u.GetDataCtx(ctx, clientDPIpath, &response, `{"type":"by_app"}`); err != nil {
+		return nil, err
+	}
 
-		for _, d := range response.Data {
-			d.SourceName = site.SourceName
-			d.SiteName = site.SiteName
-			data = append(data, d)
-		}
+	for _, d := range response.Data {
+		d.SourceName = site.SourceName
+		d.SiteName = site.SiteName
 	}
 
-	return data, nil
+	return response.Data, nil
 }
 
 // Client defines all the data a connected-network client contains.
@@ -0,0 +1,302 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrClipSegmented is returned by StreamClip when ClipOptions.MaxSegment splits the
+// time range; each segment is a separate MP4 file, so use StreamClipSegments.
+var ErrClipSegmented = fmt.Errorf("clip is split into segments; use StreamClipSegments")
+
+// ClipSegmentWriter returns the writer for one segment of a clip, numbered from 1.
+// StreamClipSegments closes the writer when the segment is written, or fails.
+type ClipSegmentWriter func(segment int, start, end time.Time) (io.WriteCloser, error)
+
+// ClipType is the kind of video exported by StreamClip.
+type ClipType string
+
+// These are the clip types the Protect export API accepts.
+const (
+	ClipTypeRotating  ClipType = "rotating"
+	ClipTypeTimelapse ClipType = "timelapse"
+)
+
+// ClipOptions configures StreamClip. The zero value exports the high quality
+// channel of the camera's primary lens, in one segment.
+type ClipOptions struct {
+	// Channel is the camera stream: 0 is high, 1 is medium and 2 is low quality.
+	Channel int
+	// Lens selects the camera lens. 0 is the primary lens; 2 is the package camera on a G4 Doorbell Pro.
+	Lens int
+	// Type is the kind of video to export. Default: ClipTypeRotating.
+	Type ClipType
+	// FPS is the frame rate of a ClipTypeTimelapse clip, which sets how much faster
+	// than real time it plays: 4 is 60x, 8 is 120x, 20 is 300x and 40 is 600x.
+	// It's ignored for other types. Default: 0, the NVR's default.
+	FPS int
+	// MaxSegment splits ranges longer than this into sequential segments that are
+	// prepared and downloaded one at a time by StreamClipSegments. Each segment is a
+	// complete MP4 file, written to its own writer. StreamClip returns ErrClipSegmented
+	// if the range is split. Default: 0, never split the range.
+	MaxSegment time.Duration
+	// Progress is called every time clip data is written.
+	Progress func(ClipProgress)
+	// prepare, if set, is held during prepare requests, so concurrent exports
+	// do not overload the NVR. See ExportClips.
+	prepare *sync.Mutex
+}
+
+// ClipProgress is passed to ClipOptions.Progress while a clip is written.
+type ClipProgress struct {
+	Segment      int // starts at 1.
+	Segments     int
+	Start        time.Time // of the current segment.
+	End          time.Time // of the current segment.
+	SegmentBytes int64     // written for the current segment.
+	Bytes        int64     // written for the whole range.
+}
+
+// values returns the prepare request parameters for one segment.
+func (o *ClipOptions) values(cameraID string, start, end time.Time) url.Values {
+	typ := o.Type
+	if typ == "" {
+		typ = ClipTypeRotating
+	}
+
+	params := url.Values{}
+	params.Set("camera", cameraID)
+	params.Set("start", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixMilli(), 10))
+	params.Set("channel", strconv.Itoa(o.Channel))
+	params.Set("lens", strconv.Itoa(o.Lens))
+	params.Set("type", string(typ))
+
+	if typ == ClipTypeTimelapse && o.FPS > 0 {
+		params.Set("fps", strconv.Itoa(o.FPS))
+	}
+
+	// The NVR keeps prepared clips by name, so the name has everything that changes the clip.
+	params.Set("filename", fmt.Sprintf("%s_%s_ch%d_lens%d_%s-%s.mp4",
+		cameraID, typ, o.Channel, o.Lens, params.Get("start"), params.Get("end")))
+
+	return params
+}
+
+// clipSegments splits a time range into pieces no longer than max.
+func clipSegments(start, end time.Time, max time.Duration) [][2]time.Time {
+	if max <= 0 || end.Sub(start) <= max {
+		return [][2]time.Time{{start, end}}
+	}
+
+	segments := [][2]time.Time{}
+
+	for from := start; from.Before(end); from = from.Add(max) {
+		to := from.Add(max)
+		if to.After(end) {
+			to = end
+		}
+
+		segments = append(segments, [2]time.Time{from, to})
+	}
+
+	return segments
+}
+
+// clipWriter counts the bytes written to a clip and reports progress.
+type clipWriter struct {
+	ClipProgress
+	w        io.Writer
+	progress func(ClipProgress)
+}
+
+func (c *clipWriter) next(w io.Writer, segment int, start, end time.Time) {
+	c.w = w
+	c.Segment = segment
+	c.Start = start
+	c.End = end
+	c.SegmentBytes = 0
+}
+
+func (c *clipWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.SegmentBytes += int64(n)
+	c.Bytes += int64(n)
+
+	if c.progress != nil && n > 0 {
+		c.progress(c.ClipProgress)
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// StreamClip prepares and downloads a clip from the specified camera for the time window,
+// and copies it to w as it's received; the clip is never held in memory. opts may be nil.
+// In testing, the prepare API can be overloaded and will start throwing 500 errors; set
+// Config.Retry to retry both requests with backoff. A download that fails part way is not
+// retried, because some of the clip was already written to w. Requesting clips from time
+// periods too close to "now" also returns a 500. Config.Timeout is not applied to the
+// download; use the context to bound it. Use StreamClipSegments to split long ranges.
+func (u *Unifi) StreamClip(
+	ctx context.Context, cameraID string, start, end time.Time, w io.Writer, opts *ClipOptions,
+) error {
+	if opts == nil {
+		opts = &ClipOptions{}
+	}
+
+	if segments := clipSegments(start, end, opts.MaxSegment); len(segments) > 1 {
+		return fmt.Errorf("%w: %d segments of %v", ErrClipSegmented, len(segments), opts.MaxSegment)
+	}
+
+	return u.streamClipSegments(ctx, cameraID, [][2]time.Time{{start, end}}, opts,
+		func(int, time.Time, time.Time) (io.WriteCloser, error) { return nopWriteCloser{w}, nil })
+}
+
+// StreamClipSegments is StreamClip for long time windows. The window is split into
+// segments no longer than opts.MaxSegment, and each segment is prepared, downloaded
+// and copied to its own writer from create, one after the other. Every segment is
+// a complete MP4 file. Segments already written are kept if a later one fails.
+func (u *Unifi) StreamClipSegments(
+	ctx context.Context, cameraID string, start, end time.Time, create ClipSegmentWriter, opts *ClipOptions,
+) error {
+	if opts == nil {
+		opts = &ClipOptions{}
+	}
+
+	return u.streamClipSegments(ctx, cameraID, clipSegments(start, end, opts.MaxSegment), opts, create)
+}
+
+func (u *Unifi) streamClipSegments(
+	ctx context.Context, cameraID string, segments [][2]time.Time, opts *ClipOptions, create ClipSegmentWriter,
+) error {
+	writer := &clipWriter{progress: opts.Progress, ClipProgress: ClipProgress{Segments: len(segments)}}
+
+	for i, segment := range segments {
+		if err := u.streamClipSegmentTo(ctx, cameraID, i+1, segment, writer, opts, create); err != nil {
+			return fmt.Errorf("clip segment %d of %d: %w", i+1, len(segments), err)
+		}
+	}
+
+	return nil
+}
+
+// streamClipSegmentTo creates the writer for a segment, streams the segment into it, and closes it.
+func (u *Unifi) streamClipSegmentTo(
+	ctx context.Context, cameraID string, idx int, segment [2]time.Time,
+	writer *clipWriter, opts *ClipOptions, create ClipSegmentWriter,
+) error {
+	w, err := create(idx, segment[0], segment[1])
+	if err != nil {
+		return fmt.Errorf("creating segment writer: %w", err)
+	}
+
+	writer.next(w, idx, segment[0], segment[1])
+	err = u.streamClipSegment(ctx, cameraID, segment[0], segment[1], writer, opts)
+
+	if closeErr := w.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("closing segment writer: %w", closeErr)
+	}
+
+	return err
+}
+
+// nopWriteCloser is StreamClip's writer for its only segment; the caller closes w.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (u *Unifi) streamClipSegment(
+	ctx context.Context, cameraID string, start, end time.Time, w io.Writer, opts *ClipOptions,
+) error {
+	prepValues := opts.values(cameraID, start, end)
+
+	// Prepare Clip Download
+	var responsePrep interface{}
+
+	if opts.prepare != nil {
+		opts.prepare.Lock()
+	}
+
+	err := u.GetDataCtx(ctx, APIProtectVideoPreparePath+"?"+prepValues.Encode(), &responsePrep)
+
+	if opts.prepare != nil {
+		opts.prepare.Unlock()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Download Clip
+	downloadValues := url.Values{}
+	downloadValues.Set("camera", cameraID)
+	downloadValues.Set("filename", prepValues.Get("filename"))
+
+	body, err := u.getStreamCtx(ctx, APIProtectVideoDownloadPath+"?"+downloadValues.Encode())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(w, body); err != nil {
+		return fmt.Errorf("copying clip: %w", err)
+	}
+
+	return nil
+}
+
+// GetClipBytes prepares and downloads a clip from the specified camera for the time window.
+// The whole clip is held in memory; use StreamClip for long time windows. See StreamClip for more.
+// Another known issue: sometimes the clip is either shorter or longer than the specified time.
+func (u *Unifi) GetClipBytes(cameraID string, start, end time.Time) ([]byte, error) {
+	return u.GetClipBytesCtx(context.Background(), cameraID, start, end)
+}
+
+// GetClipBytesCtx is the same as GetClipBytes, but both requests are bound to the provided context.
+func (u *Unifi) GetClipBytesCtx(ctx context.Context, cameraID string, start, end time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := u.StreamClip(ctx, cameraID, start, end, &buf, nil); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DownloadClip prepares and downloads a clip from the specified camera for the time window
+// into a temp file. The returned file is open and positioned at the start of the clip;
+// the caller must close and remove it. See StreamClip for more.
+func (u *Unifi) DownloadClip(cameraID string, start, end time.Time) (*os.File, error) {
+	return u.DownloadClipCtx(context.Background(), cameraID, start, end)
+}
+
+// DownloadClipCtx is the same as DownloadClip, but uses the provided context.
+func (u *Unifi) DownloadClipCtx(ctx context.Context, cameraID string, start, end time.Time) (*os.File, error) {
+	f, err := os.CreateTemp("", cameraID+"-*.mp4")
+	if err != nil {
+		return nil, fmt.Errorf("creating clip file: %w", err)
+	}
+
+	err = u.StreamClip(ctx, cameraID, start, end, f, nil)
+	if err == nil {
+		if _, err = f.Seek(0, io.SeekStart); err == nil {
+			return f, nil
+		}
+
+		err = fmt.Errorf("rewinding clip file: %w", err)
+	}
+
+	f.Close()
+	os.Remove(f.Name())
+
+	return nil, err
+}
@@ -0,0 +1,236 @@
+package unifi // nolint: testpackage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveClips answers clip prepare and download requests. Every prepare query is sent
+// to prepares, and every download returns the prepared file name as the clip.
+func serveClips(prepares chan<- url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefixNew + APIProtectVideoPreparePath:
+			prepares <- r.URL.Query()
+			_, _ = w.Write([]byte(`{"fileName":"` + r.URL.Query().Get("filename") + `"}`))
+		case APIPrefixNew + APIProtectVideoDownloadPath:
+			if r.URL.Query().Get("camera") == "broken" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			_, _ = w.Write([]byte("[" + r.URL.Query().Get("filename") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestStreamClip(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	prepares := make(chan url.Values, 10)
+	start := time.UnixMilli(1700000000000)
+	end := start.Add(150 * time.Minute)
+
+	u, _ := newProtectServer(t, serveClips(prepares))
+
+	var (
+		segments []*clipBuffer
+		progress []ClipProgress
+	)
+
+	opts := &ClipOptions{
+		Channel:    1,
+		Lens:       2,
+		Type:       ClipTypeTimelapse,
+		MaxSegment: time.Hour,
+		Progress:   func(p ClipProgress) { progress = append(progress, p) },
+	}
+
+	err := u.StreamClip(context.Background(), "cam1", start, end, io.Discard, opts)
+	a.ErrorIs(err, ErrClipSegmented, "segments can't be written to one writer.")
+	a.Empty(prepares)
+
+	err = u.StreamClipSegments(context.Background(), "cam1", start, end,
+		func(segment int, _, _ time.Time) (io.WriteCloser, error) {
+			a.Equal(len(segments)+1, segment)
+			segments = append(segments, &clipBuffer{})
+
+			return segments[len(segments)-1], nil
+		}, opts)
+	require.NoError(t, err)
+	require.Len(t, prepares, 3, "150 minutes must be split into three segments.")
+
+	for _, bounds := range [][2]string{
+		{"1700000000000", "1700003600000"},
+		{"1700003600000", "1700007200000"},
+		{"1700007200000", "1700009000000"},
+	} {
+		query := <-prepares
+		a.Equal(bounds[0], query.Get("start"))
+		a.Equal(bounds[1], query.Get("end"))
+		a.Equal("1", query.Get("channel"))
+		a.Equal("2", query.Get("lens"))
+		a.Equal("timelapse", query.Get("type"))
+	}
+
+	require.Len(t, segments, 3, "every segment must have its own writer.")
+	a.Equal("[cam1_timelapse_ch1_lens2_1700000000000-1700003600000.mp4]", segments[0].String())
+	a.Equal("[cam1_timelapse_ch1_lens2_1700003600000-1700007200000.mp4]", segments[1].String())
+	a.Equal("[cam1_timelapse_ch1_lens2_1700007200000-1700009000000.mp4]", segments[2].String())
+
+	for _, segment := range segments {
+		a.True(segment.closed, "every segment writer must be closed.")
+	}
+
+	require.Len(t, progress, 3)
+	a.Equal(3, progress[2].Segment)
+	a.Equal(3, progress[2].Segments)
+	a.Equal(end, progress[2].End)
+	a.EqualValues(3*segments[0].Len(), progress[2].Bytes)
+	a.EqualValues(segments[2].Len(), progress[2].SegmentBytes)
+
+	clip, err := u.GetClipBytes("cam1", start, end)
+	require.NoError(t, err)
+	a.Equal("[cam1_rotating_ch0_lens0_1700000000000-1700009000000.mp4]", string(clip))
+
+	query := <-prepares
+	a.Equal("0", query.Get("channel"))
+	a.Equal("0", query.Get("lens"))
+	a.Equal("rotating", query.Get("type"))
+
+	err = u.StreamClip(context.Background(), "broken", start, end, io.Discard, nil)
+	a.ErrorIs(err, ErrInvalidStatusCode)
+	a.Contains(err.Error(), "clip segment 1 of 1")
+}
+
+// clipBuffer is a clip segment writer that records when it's closed.
+type clipBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *clipBuffer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestDownloadClip(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	prepares := make(chan url.Values, 10)
+	start := time.UnixMilli(1700000000000)
+
+	u, _ := newProtectServer(t, serveClips(prepares))
+
+	f, err := u.DownloadClip("cam1", start, start.Add(time.Minute))
+	require.NoError(t, err)
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	a.True(strings.HasSuffix(f.Name(), ".mp4"))
+
+	clip, err := io.ReadAll(f)
+	require.NoError(t, err)
+	a.Equal("[cam1_rotating_ch0_lens0_1700000000000-1700000060000.mp4]", string(clip), "the file must be rewound.")
+
+	f, err = u.DownloadClip("broken", start, start.Add(time.Minute))
+	a.Nil(f)
+	a.ErrorIs(err, ErrInvalidStatusCode)
+}
+
+func TestExportClips(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	prepares := make(chan url.Values, 10)
+	start := time.UnixMilli(1700000000000)
+	end := start.Add(time.Minute)
+	clips := serveClips(prepares)
+	preparing, overlapped := atomic.Int32{}, atomic.Bool{}
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefixNew + "/api/cameras":
+			_, _ = w.Write([]byte(`[{"id":"cam1","name":"Front"},{"id":"cam2","name":""},{"id":"broken","name":"Gate"}]`))
+		case APIPrefixNew + APIProtectVideoPreparePath:
+			if preparing.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			preparing.Add(-1)
+			clips(w, r)
+		default:
+			clips(w, r)
+		}
+	})
+
+	dir := filepath.Join(t.TempDir(), "incident")
+	progress := atomic.Int32{}
+	manifest, err := u.ExportClips([]string{"cam1", "cam2", "missing", "cam1", "broken"}, start, end, dir, &ExportOptions{
+		Clip:     ClipOptions{Type: ClipTypeTimelapse, FPS: 20},
+		Parallel: 4,
+		Progress: func(string, ClipProgress) { progress.Add(1) },
+	})
+	a.ErrorIs(err, ErrNotFound)
+	a.ErrorIs(err, ErrInvalidStatusCode, "every camera error must be returned.")
+	a.False(overlapped.Load(), "prepare requests must not be sent at the same time.")
+	a.EqualValues(2, progress.Load())
+
+	for range 3 {
+		query := <-prepares
+		a.Equal("timelapse", query.Get("type"))
+		a.Equal("20", query.Get("fps"))
+	}
+
+	require.Len(t, manifest.Clips, 4, "repeated cameras must be exported once.")
+	a.Equal(20, manifest.FPS)
+	a.Equal("Front", manifest.Clips[0].CameraName)
+	a.Equal("cam1_timelapse_ch0_lens0_1700000000000-1700000060000.mp4", manifest.Clips[0].File)
+	a.Empty(manifest.Clips[1].CameraName)
+	a.Empty(manifest.Clips[1].Error, "a camera without a name must be exported.")
+	a.Contains(manifest.Clips[2].Error, "not found")
+	a.Empty(manifest.Clips[3].File)
+	a.NotEmpty(manifest.Clips[3].Error)
+
+	data, err := os.ReadFile(filepath.Join(dir, manifest.Clips[1].File))
+	require.NoError(t, err)
+	a.Equal("[cam2_timelapse_ch0_lens0_1700000000000-1700000060000.mp4]", string(data))
+	a.EqualValues(len(data), manifest.Clips[1].Bytes)
+	a.Len(manifest.Clips[1].SHA256, 64)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	a.Len(files, 3, "failed clips must be removed, and the manifest written.")
+
+	var written ExportManifest
+
+	data, err = os.ReadFile(filepath.Join(dir, ExportManifestFile))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &written))
+	a.Equal(manifest.Clips[0].SHA256, written.Clips[0].SHA256)
+	a.True(start.Equal(written.Start))
+
+	other, err := u.ExportClips([]string{"cam1"}, start, end, dir, &ExportOptions{Clip: ClipOptions{Channel: 1}})
+	require.NoError(t, err)
+	a.Equal("cam1_rotating_ch1_lens0_1700000000000-1700000060000.mp4", other.Clips[0].File)
+	a.FileExists(filepath.Join(dir, manifest.Clips[0].File), "another channel must not overwrite the clip.")
+
+	_, err = u.ExportClips([]string{"cam1"}, start, end, dir, &ExportOptions{Clip: ClipOptions{MaxSegment: time.Second}})
+	a.ErrorIs(err, ErrClipSegmented, "a camera's clip must be one file.")
+}
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -8,19 +9,27 @@ import (
 
 // GetDevices returns a response full of devices' data from the UniFi Controller.
 func (u *Unifi) GetDevices(sites []*Site) (*Devices, error) {
-	devices := new(Devices)
+	return u.GetDevicesCtx(context.Background(), sites)
+}
 
-	for _, site := range sites {
+// GetDevicesCtx is the same as GetDevices, but uses the provided context.
+func (u *Unifi) GetDevicesCtx(ctx context.Context, sites []*Site) (*Devices, error) {
+	list, err := eachSite(ctx, u, sites, func(ctx context.Context, site *Site) ([]*Devices, error) {
 		var response struct {
 			Data []json.RawMessage `json:"data"`
 		}
 
 		devicePath := fmt.Sprintf(APIDevicePath, site.Name)
-		if err := u.GetData(devicePath, &response); err != nil {
+		if err := u.GetDataCtx(ctx, devicePath, &response); err != nil {
 			return nil, err
 		}
 
-		loopDevices := u.parseDevices(response.Data, site)
+		return []*Devices{u.parseDevices(response.Data, site)}, nil
+	})
+
+	devices := new(Devices)
+
+	for _, loopDevices := range list {
 		devices.UAPs = append(devices.UAPs, loopDevices.UAPs...)
 		devices.USGs = append(devices.USGs, loopDevices.USGs...)
 		devices.USWs = append(devices.USWs, loopDevices.USWs...)
@@ -29,16 +38,21 @@ func (u *Unifi) GetDevices(sites []*Site) (*Devices, error) {
 		devices.PDUs = append(devices.PDUs, loopDevices.PDUs...)
 	}
 
-	return devices, nil
+	return devices, err
 }
 
 // GetUSWs returns all switches, an error, or nil if there are no switches.
 func (u *Unifi) GetUSWs(site *Site) ([]*USW, error) {
+	return u.GetUSWsCtx(context.Background(), site)
+}
+
+// GetUSWsCtx is the same as GetUSWs, but uses the provided context.
+func (u *Unifi) GetUSWsCtx(ctx context.Context, site *Site) ([]*USW, error) {
 	var response struct {
 		Data []json.RawMessage `json:"data"`
 	}
 
-	err := u.GetData(fmt.Sprintf(APIDevicePath, site.Name), &response)
+	err := u.GetDataCtx(ctx, fmt.Sprintf(APIDevicePath, site.Name), &response)
 	if err != nil {
 		return nil, err
 	}
@@ -48,11 +62,16 @@ func (u *Unifi) GetUSWs(site *Site) ([]*USW, error) {
 
 // GetUAPs returns all access points, an error, or nil if there are no APs.
 func (u *Unifi) GetUAPs(site *Site) ([]*UAP, error) {
+	return u.GetUAPsCtx(context.Background(), site)
+}
+
+// GetUAPsCtx is the same as GetUAPs, but uses the provided context.
+func (u *Unifi) GetUAPsCtx(ctx context.Context, site *Site) ([]*UAP, error) {
 	var response struct {
 		Data []json.RawMessage `json:"data"`
 	}
 
-	err := u.GetData(fmt.Sprintf(APIDevicePath, site.Name), &response)
+	err := u.GetDataCtx(ctx, fmt.Sprintf(APIDevicePath, site.Name), &response)
 	if err != nil {
 		return nil, err
 	}
@@ -62,11 +81,16 @@ func (u *Unifi) GetUAPs(site *Site) ([]*UAP, error) {
 
 // GetUDMs returns all dream machines, an error, or nil if there are no UDMs.
 func (u *Unifi) GetUDMs(site *Site) ([]*UDM, error) {
+	return u.GetUDMsCtx(context.Background(), site)
+}
+
+// GetUDMsCtx is the same as GetUDMs, but uses the provided context.
+func (u *Unifi) GetUDMsCtx(ctx context.Context, site *Site) ([]*UDM, error) {
 	var response struct {
 		Data []json.RawMessage `json:"data"`
 	}
 
-	err := u.GetData(fmt.Sprintf(APIDevicePath, site.Name), &response)
+	err := u.GetDataCtx(ctx, fmt.Sprintf(APIDevicePath, site.Name), &response)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +100,16 @@ func (u *Unifi) GetUDMs(site *Site) ([]*UDM, error) {
 
 // GetUXGs returns all 10Gb gateways, an error, or nil if there are no UXGs.
 func (u *Unifi) GetUXGs(site *Site) ([]*UXG, error) {
+	return u.GetUXGsCtx(context.Background(), site)
+}
+
+// GetUXGsCtx is the same as GetUXGs, but uses the provided context.
+func (u *Unifi) GetUXGsCtx(ctx context.Context, site *Site) ([]*UXG, error) {
 	var response struct {
 		Data []json.RawMessage `json:"data"`
 	}
 
-	err := u.GetData(fmt.Sprintf(APIDevicePath, site.Name), &response)
+	err := u.GetDataCtx(ctx, fmt.Sprintf(APIDevicePath, site.Name), &response)
 	if err != nil {
 		return nil, err
 	}
@@ -90,11 +119,16 @@ func (u *Unifi) GetUXGs(site *Site) ([]*UXG, error) {
 
 // GetUSGs returns all 1Gb gateways, an error, or nil if there are no USGs.
 func (u *Unifi) GetUSGs(site *Site) ([]*USG, error) {
+	return u.GetUSGsCtx(context.Background(), site)
+}
+
+// GetUSGsCtx is the same as GetUSGs, but uses the provided context.
+func (u *Unifi) GetUSGsCtx(ctx context.Context, site *Site) ([]*USG, error) {
 	var response struct {
 		Data []json.RawMessage `json:"data"`
 	}
 
-	err := u.GetData(fmt.Sprintf(APIDevicePath, site.Name), &response)
+	err := u.GetDataCtx(ctx, fmt.Sprintf(APIDevicePath, site.Name), &response)
 	if err != nil {
 		return nil, err
 	}
@@ -114,91 +148,93 @@ func (u *Unifi) parseDevices(data []json.RawMessage, site *Site) *Devices {
 	for _, r := range data {
 		// Loop each item in the raw JSON message, detect its type and unmarshal it.
 		var o minimalUnmarshalInfo
-		if u.unmarshalDevice("map", r, &o) != nil {
-			u.ErrorLog("unknown asset type - cannot find asset type in payload - skipping")
-			
+		if u.unmarshalDevice("map", r, &o, "site", site.SiteName) != nil {
+			u.logger().Error("unknown asset type - cannot find asset type in payload - skipping",
+				"site", site.SiteName)
+
 			continue
 		}
 
 		assetType := o.Type
 		model := o.Model
-		u.DebugLog("Unmarshalling Device Type: %v, Model: %s, site %s ", assetType, model, site.SiteName)
+		attrs := []any{"site", site.SiteName, "type", assetType, "model", model}
+		u.logger().Debug("Unmarshalling Device", attrs...)
 		// Choose which type to unmarshal into based on the "type" json key.
 
 		switch assetType { // Unmarshal again into the correct type..
 		case "uap":
-			u.unmarshallUAP(site, r, devices)
+			u.unmarshallUAP(site, r, devices, attrs)
 		case "ugw", "usg": // in case they ever fix the name in the api.
-			u.unmarshallUSG(site, r, devices)
+			u.unmarshallUSG(site, r, devices, attrs)
 		case "usw":
 			if strings.Contains(strings.ToLower(model), "pdu") {
 				// this may actually happen, unifi APIs are all over the place
-				u.unmarshallPDU(site, r, devices)
+				u.unmarshallPDU(site, r, devices, attrs)
 			} else {
-				u.unmarshallUSW(site, r, devices)
+				u.unmarshallUSW(site, r, devices, attrs)
 			}
 		case "pdu":
-			u.unmarshallPDU(site, r, devices)
+			u.unmarshallPDU(site, r, devices, attrs)
 		case "udm":
-			u.unmarshallUDM(site, r, devices)
+			u.unmarshallUDM(site, r, devices, attrs)
 		case "uxg":
-			u.unmarshallUXG(site, r, devices)
+			u.unmarshallUXG(site, r, devices, attrs)
 		default:
-			u.ErrorLog("unknown asset type - %v - skipping: data=%+v", assetType, string(r))
+			u.logger().Error("unknown asset type - skipping", append(attrs, "data", string(r))...)
 		}
 	}
 
 	return devices
 }
 
-func (u *Unifi) unmarshallUAP(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallUAP(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &UAP{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("uap", payload, dev) == nil {
+	if u.unmarshalDevice("uap", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.UAPs = append(devices.UAPs, dev)
 	}
 }
 
-func (u *Unifi) unmarshallUSG(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallUSG(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &USG{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("ugw", payload, dev) == nil {
+	if u.unmarshalDevice("ugw", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.USGs = append(devices.USGs, dev)
 	}
 }
 
-func (u *Unifi) unmarshallUSW(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallUSW(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &USW{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("usw", payload, dev) == nil {
+	if u.unmarshalDevice("usw", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.USWs = append(devices.USWs, dev)
 	}
 }
 
-func (u *Unifi) unmarshallPDU(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallPDU(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &PDU{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("usw", payload, dev) == nil {
+	if u.unmarshalDevice("usw", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.PDUs = append(devices.PDUs, dev)
 	}
 }
 
-func (u *Unifi) unmarshallUXG(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallUXG(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &UXG{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("uxg", payload, dev) == nil {
+	if u.unmarshalDevice("uxg", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.UXGs = append(devices.UXGs, dev)
 	}
 }
 
-func (u *Unifi) unmarshallUDM(site *Site, payload json.RawMessage, devices *Devices) {
+func (u *Unifi) unmarshallUDM(site *Site, payload json.RawMessage, devices *Devices, attrs []any) {
 	dev := &UDM{SiteName: site.SiteName, SourceName: u.URL}
-	if u.unmarshalDevice("udm", payload, dev) == nil {
+	if u.unmarshalDevice("udm", payload, dev, attrs...) == nil {
 		dev.Name = strings.TrimSpace(pick(dev.Name, dev.Mac))
 		dev.site = site
 		devices.UDMs = append(devices.UDMs, dev)
@@ -206,15 +242,21 @@ func (u *Unifi) unmarshallUDM(site *Site, payload json.RawMessage, devices *Devi
 }
 
 // unmarshalDevice handles logging for the unmarshal operations in parseDevices().
-func (u *Unifi) unmarshalDevice(dev string, data json.RawMessage, v interface{}) (err error) {
+// The attrs are added to the log lines, as key value pairs like those passed to slog.
+func (u *Unifi) unmarshalDevice(dev string, data json.RawMessage, v interface{}, attrs ...any) (err error) {
 	if err = json.Unmarshal(data, v); err != nil {
-		u.ErrorLog("json.Unmarshal(%v): %v", dev, err)
-		u.ErrorLog("Enable Debug Logging to output the failed payload.")
+		attrs = append([]any{"into", dev}, attrs...)
+		u.logger().Error("json.Unmarshal failed", append(attrs, "error", err)...)
+		u.logger().Error("Enable Debug Logging to output the failed payload.")
 
 		json, err := data.MarshalJSON()
-		u.DebugLog("Failed Payload: %s (marshal err: %v)", json, err)
-		u.DebugLog("The above payload can prove useful during torubleshooting when you open an Issue:")
-		u.DebugLog("==- https://github.com/unifi-poller/unifi/issues/new -==")
+		if err != nil {
+			attrs = append(attrs, "marshal_error", err)
+		}
+
+		u.logger().Debug("Failed Payload", append(attrs, "payload", string(json))...)
+		u.logger().Debug("The above payload can prove useful during torubleshooting when you open an Issue:")
+		u.logger().Debug("==- https://github.com/unifi-poller/unifi/issues/new -==")
 	}
 
 	if err != nil {
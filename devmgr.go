@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -26,21 +27,21 @@ const (
 
 // devMgrCmd is the type marshalled and sent to APIDevMgrPath.
 type devMgrCmd struct {
-	Cmd    string `json:"cmd"`                               // Required.
+	Cmd    string `json:"cmd"`                                            // Required.
 	Inform string `fake:"{url}"              json:"inform_url,omitempty"` // Migration only.
-	Mac    string `fake:"{macaddress}"       json:"mac"`           // Device MAC (required for most, but not all).
-	Port   int    `json:"port_idx,omitempty"`                // Power Cycle only.
+	Mac    string `fake:"{macaddress}"       json:"mac"`                  // Device MAC (required for most, but not all).
+	Port   int    `json:"port_idx,omitempty"`                             // Power Cycle only.
 	URL    string `fake:"{url}"              json:"url,omitempty"`        // External Upgrade only.
 }
 
 // devMgrCommandReply is for commands with a return value.
-func (s *Site) devMgrCommandReply(cmd *devMgrCmd) ([]byte, error) {
+func (s *Site) devMgrCommandReply(ctx context.Context, cmd *devMgrCmd) ([]byte, error) {
 	data, err := json.Marshal(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("json marshal: %w", err)
 	}
 
-	b, err := s.controller.GetJSON(fmt.Sprintf(APIDevMgrPath, s.Name), string(data))
+	b, err := s.controller.GetJSONCtx(ctx, fmt.Sprintf(APIDevMgrPath, s.Name), string(data))
 	if err != nil {
 		return nil, fmt.Errorf("controller: %w", err)
 	}
@@ -49,16 +50,21 @@ func (s *Site) devMgrCommandReply(cmd *devMgrCmd) ([]byte, error) {
 }
 
 // devMgrCommandSimple is for commands with no return value.
-func (s *Site) devMgrCommandSimple(cmd *devMgrCmd) error {
-	_, err := s.devMgrCommandReply(cmd)
-	
+func (s *Site) devMgrCommandSimple(ctx context.Context, cmd *devMgrCmd) error {
+	_, err := s.devMgrCommandReply(ctx, cmd)
+
 	return err
 }
 
 // PowerCycle shuts off the PoE and turns it back on for a specific port.
 // Get a USW from the device list to call this.
 func (u *USW) PowerCycle(portIndex int) error {
-	return u.site.devMgrCommandSimple(&devMgrCmd{
+	return u.PowerCycleCtx(context.Background(), portIndex)
+}
+
+// PowerCycleCtx is the same as PowerCycle, but uses the provided context.
+func (u *USW) PowerCycleCtx(ctx context.Context, portIndex int) error {
+	return u.site.devMgrCommandSimple(ctx, &devMgrCmd{
 		Cmd:  DevMgrPowerCycle,
 		Mac:  u.Mac,
 		Port: portIndex,
@@ -67,235 +73,455 @@ func (u *USW) PowerCycle(portIndex int) error {
 
 // ScanRF begins a spectrum scan on an access point.
 func (u *UAP) ScanRF() error {
-	return u.site.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrSpectrumScan, Mac: u.Mac})
+	return u.ScanRFCtx(context.Background())
+}
+
+// ScanRFCtx is the same as ScanRF, but uses the provided context.
+func (u *UAP) ScanRFCtx(ctx context.Context) error {
+	return u.site.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrSpectrumScan, Mac: u.Mac})
 }
 
 // Restart a device by MAC address on your site.
 func (s *Site) Restart(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrRestart, Mac: mac})
+	return s.RestartCtx(context.Background(), mac)
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (s *Site) RestartCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrRestart, Mac: mac})
 }
 
 // Restart an access point.
 func (u *UAP) Restart() error {
-	return u.site.Restart(u.Mac)
+	return u.RestartCtx(context.Background())
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (u *UAP) RestartCtx(ctx context.Context) error {
+	return u.site.RestartCtx(ctx, u.Mac)
 }
 
 // Restart a switch.
 func (u *USW) Restart() error {
-	return u.site.Restart(u.Mac)
+	return u.RestartCtx(context.Background())
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (u *USW) RestartCtx(ctx context.Context) error {
+	return u.site.RestartCtx(ctx, u.Mac)
 }
 
 // Restart a security gateway.
 func (u *USG) Restart() error {
-	return u.site.Restart(u.Mac)
+	return u.RestartCtx(context.Background())
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (u *USG) RestartCtx(ctx context.Context) error {
+	return u.site.RestartCtx(ctx, u.Mac)
 }
 
 // Restart a dream machine.
 func (u *UDM) Restart() error {
-	return u.site.Restart(u.Mac)
+	return u.RestartCtx(context.Background())
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (u *UDM) RestartCtx(ctx context.Context) error {
+	return u.site.RestartCtx(ctx, u.Mac)
 }
 
 // Restart a 10Gb security gateway.
 func (u *UXG) Restart() error {
-	return u.site.Restart(u.Mac)
+	return u.RestartCtx(context.Background())
+}
+
+// RestartCtx is the same as Restart, but uses the provided context.
+func (u *UXG) RestartCtx(ctx context.Context) error {
+	return u.site.RestartCtx(ctx, u.Mac)
 }
 
 // Locate a device by MAC address on your site. This makes it blink.
 func (s *Site) Locate(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrSetLocate, Mac: mac})
+	return s.LocateCtx(context.Background(), mac)
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (s *Site) LocateCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrSetLocate, Mac: mac})
 }
 
 // Locate an access point.
 func (u *UAP) Locate() error {
-	return u.site.Locate(u.Mac)
+	return u.LocateCtx(context.Background())
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (u *UAP) LocateCtx(ctx context.Context) error {
+	return u.site.LocateCtx(ctx, u.Mac)
 }
 
 // Locate a switch.
 func (u *USW) Locate() error {
-	return u.site.Locate(u.Mac)
+	return u.LocateCtx(context.Background())
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (u *USW) LocateCtx(ctx context.Context) error {
+	return u.site.LocateCtx(ctx, u.Mac)
 }
 
 // Locate a security gateway.
 func (u *USG) Locate() error {
-	return u.site.Locate(u.Mac)
+	return u.LocateCtx(context.Background())
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (u *USG) LocateCtx(ctx context.Context) error {
+	return u.site.LocateCtx(ctx, u.Mac)
 }
 
 // Locate a dream machine.
 func (u *UDM) Locate() error {
-	return u.site.Locate(u.Mac)
+	return u.LocateCtx(context.Background())
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (u *UDM) LocateCtx(ctx context.Context) error {
+	return u.site.LocateCtx(ctx, u.Mac)
 }
 
 // Locate a 10Gb security gateway.
 func (u *UXG) Locate() error {
-	return u.site.Locate(u.Mac)
+	return u.LocateCtx(context.Background())
+}
+
+// LocateCtx is the same as Locate, but uses the provided context.
+func (u *UXG) LocateCtx(ctx context.Context) error {
+	return u.site.LocateCtx(ctx, u.Mac)
 }
 
 // Unlocate a device by MAC address on your site. This makes it stop blinking.
 func (s *Site) Unlocate(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrUnsetLocate, Mac: mac})
+	return s.UnlocateCtx(context.Background(), mac)
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (s *Site) UnlocateCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrUnsetLocate, Mac: mac})
 }
 
 // Unlocate an access point (stop blinking).
 func (u *UAP) Unlocate() error {
-	return u.site.Unlocate(u.Mac)
+	return u.UnlocateCtx(context.Background())
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (u *UAP) UnlocateCtx(ctx context.Context) error {
+	return u.site.UnlocateCtx(ctx, u.Mac)
 }
 
 // Unlocate a switch (stop blinking).
 func (u *USW) Unlocate() error {
-	return u.site.Unlocate(u.Mac)
+	return u.UnlocateCtx(context.Background())
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (u *USW) UnlocateCtx(ctx context.Context) error {
+	return u.site.UnlocateCtx(ctx, u.Mac)
 }
 
 // Unlocate a security gateway (stop blinking).
 func (u *USG) Unlocate() error {
-	return u.site.Unlocate(u.Mac)
+	return u.UnlocateCtx(context.Background())
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (u *USG) UnlocateCtx(ctx context.Context) error {
+	return u.site.UnlocateCtx(ctx, u.Mac)
 }
 
 // Unlocate a dream machine (stop blinking).
 func (u *UDM) Unlocate() error {
-	return u.site.Unlocate(u.Mac)
+	return u.UnlocateCtx(context.Background())
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (u *UDM) UnlocateCtx(ctx context.Context) error {
+	return u.site.UnlocateCtx(ctx, u.Mac)
 }
 
 // Unlocate a 10Gb security gateway (stop blinking).
 func (u *UXG) Unlocate() error {
-	return u.site.Unlocate(u.Mac)
+	return u.UnlocateCtx(context.Background())
+}
+
+// UnlocateCtx is the same as Unlocate, but uses the provided context.
+func (u *UXG) UnlocateCtx(ctx context.Context) error {
+	return u.site.UnlocateCtx(ctx, u.Mac)
 }
 
 // Provision force provisions a device by MAC address on your site.
 func (s *Site) Provision(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrForceProvision, Mac: mac})
+	return s.ProvisionCtx(context.Background(), mac)
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (s *Site) ProvisionCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrForceProvision, Mac: mac})
 }
 
 // Provision an access point forcefully.
 func (u *UAP) Provision() error {
-	return u.site.Provision(u.Mac)
+	return u.ProvisionCtx(context.Background())
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (u *UAP) ProvisionCtx(ctx context.Context) error {
+	return u.site.ProvisionCtx(ctx, u.Mac)
 }
 
 // Provision a switch forcefully.
 func (u *USW) Provision() error {
-	return u.site.Provision(u.Mac)
+	return u.ProvisionCtx(context.Background())
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (u *USW) ProvisionCtx(ctx context.Context) error {
+	return u.site.ProvisionCtx(ctx, u.Mac)
 }
 
 // Provision a security gateway forcefully.
 func (u *USG) Provision() error {
-	return u.site.Provision(u.Mac)
+	return u.ProvisionCtx(context.Background())
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (u *USG) ProvisionCtx(ctx context.Context) error {
+	return u.site.ProvisionCtx(ctx, u.Mac)
 }
 
 // Provision a dream machine forcefully.
 func (u *UDM) Provision() error {
-	return u.site.Provision(u.Mac)
+	return u.ProvisionCtx(context.Background())
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (u *UDM) ProvisionCtx(ctx context.Context) error {
+	return u.site.ProvisionCtx(ctx, u.Mac)
 }
 
 // Provision a 10Gb security gateway forcefully.
 func (u *UXG) Provision() error {
-	return u.site.Provision(u.Mac)
+	return u.ProvisionCtx(context.Background())
+}
+
+// ProvisionCtx is the same as Provision, but uses the provided context.
+func (u *UXG) ProvisionCtx(ctx context.Context) error {
+	return u.site.ProvisionCtx(ctx, u.Mac)
 }
 
 // Upgrade starts a firmware upgrade on a device by MAC address on your site.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (s *Site) Upgrade(mac string, url string) error {
+	return s.UpgradeCtx(context.Background(), mac, url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (s *Site) UpgradeCtx(ctx context.Context, mac string, url string) error {
 	if url == "" {
-		return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrUpgrade, Mac: mac})
+		return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrUpgrade, Mac: mac})
 	}
 
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrUpgradeExternal, Mac: mac, URL: url})
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrUpgradeExternal, Mac: mac, URL: url})
 }
 
 // Upgrade firmware on an access point.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (u *UAP) Upgrade(url string) error {
-	return u.site.Upgrade(u.Mac, url)
+	return u.UpgradeCtx(context.Background(), url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (u *UAP) UpgradeCtx(ctx context.Context, url string) error {
+	return u.site.UpgradeCtx(ctx, u.Mac, url)
 }
 
 // Upgrade firmware on a switch.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (u *USW) Upgrade(url string) error {
-	return u.site.Upgrade(u.Mac, url)
+	return u.UpgradeCtx(context.Background(), url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (u *USW) UpgradeCtx(ctx context.Context, url string) error {
+	return u.site.UpgradeCtx(ctx, u.Mac, url)
 }
 
 // Upgrade firmware on a security gateway.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (u *USG) Upgrade(url string) error {
-	return u.site.Upgrade(u.Mac, url)
+	return u.UpgradeCtx(context.Background(), url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (u *USG) UpgradeCtx(ctx context.Context, url string) error {
+	return u.site.UpgradeCtx(ctx, u.Mac, url)
 }
 
 // Upgrade firmware on a dream machine.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (u *UDM) Upgrade(url string) error {
-	return u.site.Upgrade(u.Mac, url)
+	return u.UpgradeCtx(context.Background(), url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (u *UDM) UpgradeCtx(ctx context.Context, url string) error {
+	return u.site.UpgradeCtx(ctx, u.Mac, url)
 }
 
 // Upgrade formware on a 10Gb security gateway.
 // URL is optional. If URL is not "" an external upgrade is performed.
 func (u *UXG) Upgrade(url string) error {
-	return u.site.Upgrade(u.Mac, url)
+	return u.UpgradeCtx(context.Background(), url)
+}
+
+// UpgradeCtx is the same as Upgrade, but uses the provided context.
+func (u *UXG) UpgradeCtx(ctx context.Context, url string) error {
+	return u.site.UpgradeCtx(ctx, u.Mac, url)
 }
 
 // Migrate sends a device to another controller's URL.
 // Probably does not work on devices with built-in controllers like UDM & UXG.
 func (s *Site) Migrate(mac string, url string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrMigrate, Mac: mac, Inform: url})
+	return s.MigrateCtx(context.Background(), mac, url)
+}
+
+// MigrateCtx is the same as Migrate, but uses the provided context.
+func (s *Site) MigrateCtx(ctx context.Context, mac string, url string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrMigrate, Mac: mac, Inform: url})
 }
 
 // Migrate sends an access point to another controller's URL.
 func (u *UAP) Migrate(url string) error {
-	return u.site.Migrate(u.Mac, url)
+	return u.MigrateCtx(context.Background(), url)
+}
+
+// MigrateCtx is the same as Migrate, but uses the provided context.
+func (u *UAP) MigrateCtx(ctx context.Context, url string) error {
+	return u.site.MigrateCtx(ctx, u.Mac, url)
 }
 
 // Migrate sends a switch to another controller's URL.
 func (u *USW) Migrate(url string) error {
-	return u.site.Migrate(u.Mac, url)
+	return u.MigrateCtx(context.Background(), url)
+}
+
+// MigrateCtx is the same as Migrate, but uses the provided context.
+func (u *USW) MigrateCtx(ctx context.Context, url string) error {
+	return u.site.MigrateCtx(ctx, u.Mac, url)
 }
 
 // Migrate sends a security gateway to another controller's URL.
 func (u *USG) Migrate(url string) error {
-	return u.site.Migrate(u.Mac, url)
+	return u.MigrateCtx(context.Background(), url)
+}
+
+// MigrateCtx is the same as Migrate, but uses the provided context.
+func (u *USG) MigrateCtx(ctx context.Context, url string) error {
+	return u.site.MigrateCtx(ctx, u.Mac, url)
 }
 
 // Migrate sends a 10Gb gateway to another controller's URL.
 func (u *UXG) Migrate(url string) error {
-	return u.site.Migrate(u.Mac, url)
+	return u.MigrateCtx(context.Background(), url)
+}
+
+// MigrateCtx is the same as Migrate, but uses the provided context.
+func (u *UXG) MigrateCtx(ctx context.Context, url string) error {
+	return u.site.MigrateCtx(ctx, u.Mac, url)
 }
 
 // CancelMigrate stops a migration in progress.
 // Probably does not work on devices with built-in controllers like UDM & UXG.
 func (s *Site) CancelMigrate(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrCancelMigrate, Mac: mac})
+	return s.CancelMigrateCtx(context.Background(), mac)
+}
+
+// CancelMigrateCtx is the same as CancelMigrate, but uses the provided context.
+func (s *Site) CancelMigrateCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrCancelMigrate, Mac: mac})
 }
 
 // CancelMigrate stops an access point migration in progress.
 func (u *UAP) CancelMigrate() error {
-	return u.site.CancelMigrate(u.Mac)
+	return u.CancelMigrateCtx(context.Background())
+}
+
+// CancelMigrateCtx is the same as CancelMigrate, but uses the provided context.
+func (u *UAP) CancelMigrateCtx(ctx context.Context) error {
+	return u.site.CancelMigrateCtx(ctx, u.Mac)
 }
 
 // CancelMigrate stops a switch migration in progress.
 func (u *USW) CancelMigrate() error {
-	return u.site.CancelMigrate(u.Mac)
+	return u.CancelMigrateCtx(context.Background())
+}
+
+// CancelMigrateCtx is the same as CancelMigrate, but uses the provided context.
+func (u *USW) CancelMigrateCtx(ctx context.Context) error {
+	return u.site.CancelMigrateCtx(ctx, u.Mac)
 }
 
 // CancelMigrate stops a security gateway migration in progress.
 func (u *USG) CancelMigrate() error {
-	return u.site.CancelMigrate(u.Mac)
+	return u.CancelMigrateCtx(context.Background())
+}
+
+// CancelMigrateCtx is the same as CancelMigrate, but uses the provided context.
+func (u *USG) CancelMigrateCtx(ctx context.Context) error {
+	return u.site.CancelMigrateCtx(ctx, u.Mac)
 }
 
 // CancelMigrate stops 10Gb gateway a migration in progress.
 func (u *UXG) CancelMigrate() error {
-	return u.site.CancelMigrate(u.Mac)
+	return u.CancelMigrateCtx(context.Background())
+}
+
+// CancelMigrateCtx is the same as CancelMigrate, but uses the provided context.
+func (u *UXG) CancelMigrateCtx(ctx context.Context) error {
+	return u.site.CancelMigrateCtx(ctx, u.Mac)
 }
 
 // Adopt a device by MAC address to your site.
 func (s *Site) Adopt(mac string) error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrAdopt, Mac: mac})
+	return s.AdoptCtx(context.Background(), mac)
+}
+
+// AdoptCtx is the same as Adopt, but uses the provided context.
+func (s *Site) AdoptCtx(ctx context.Context, mac string) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrAdopt, Mac: mac})
 }
 
 // SpeedTest begins a speed test on a site.
 func (s *Site) SpeedTest() error {
-	return s.devMgrCommandSimple(&devMgrCmd{Cmd: DevMgrSpeedTest})
+	return s.SpeedTestCtx(context.Background())
+}
+
+// SpeedTestCtx is the same as SpeedTest, but uses the provided context.
+func (s *Site) SpeedTestCtx(ctx context.Context) error {
+	return s.devMgrCommandSimple(ctx, &devMgrCmd{Cmd: DevMgrSpeedTest})
 }
 
 // SpeedTestStatus returns the raw response for the status of a speed test.
 // XXX: marshal the response into a data structure. This method will change!
 func (s *Site) SpeedTestStatus() ([]byte, error) {
-	body, err := s.devMgrCommandReply(&devMgrCmd{Cmd: DevMgrSpeedTestStatus})
+	return s.SpeedTestStatusCtx(context.Background())
+}
+
+// SpeedTestStatusCtx is the same as SpeedTestStatus, but uses the provided context.
+func (s *Site) SpeedTestStatusCtx(ctx context.Context) ([]byte, error) {
+	body, err := s.devMgrCommandReply(ctx, &devMgrCmd{Cmd: DevMgrSpeedTestStatus})
 	// marshal into struct here.
 	return body, err
 }
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -18,22 +19,23 @@ const (
 
 // GetEvents returns a response full of UniFi Events for the last 1 hour from multiple sites.
 func (u *Unifi) GetEvents(sites []*Site, hours time.Duration) ([]*Event, error) {
-	data := make([]*Event, 0)
-
-	for _, site := range sites {
-		response, err := u.GetSiteEvents(site, hours)
-		if err != nil {
-			return data, err
-		}
-
-		data = append(data, response...)
-	}
+	return u.GetEventsCtx(context.Background(), sites, hours)
+}
 
-	return data, nil
+// GetEventsCtx is the same as GetEvents, but uses the provided context.
+func (u *Unifi) GetEventsCtx(ctx context.Context, sites []*Site, hours time.Duration) ([]*Event, error) {
+	return eachSite(ctx, u, sites, func(ctx context.Context, site *Site) ([]*Event, error) {
+		return u.GetSiteEventsCtx(ctx, site, hours)
+	})
 }
 
 // GetSiteEvents retrieves the last 1 hour's worth of events from a single site.
 func (u *Unifi) GetSiteEvents(site *Site, hours time.Duration) ([]*Event, error) {
+	return u.GetSiteEventsCtx(context.Background(), site, hours)
+}
+
+// GetSiteEventsCtx is the same as GetSiteEvents, but uses the provided context.
+func (u *Unifi) GetSiteEventsCtx(ctx context.Context, site *Site, hours time.Duration) ([]*Event, error) {
 	if site == nil || site.Name == "" {
 		return nil, ErrNoSiteProvided
 	}
@@ -53,7 +55,7 @@ func (u *Unifi) GetSiteEvents(site *Site, hours time.Duration) ([]*Event, error)
 		}
 	)
 
-	if err := u.GetData(path, &event, params); err != nil {
+	if err := u.GetDataCtx(ctx, path, &event, params); err != nil {
 		return event.Data, err
 	}
 
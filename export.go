@@ -0,0 +1,242 @@
+package unifi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultExportParallel is how many cameras ExportClips downloads at once.
+const DefaultExportParallel = 3
+
+// ExportManifestFile is the name of the manifest ExportClips writes with the clips.
+const ExportManifestFile = "manifest.json"
+
+// ExportOptions configures ExportClips. The zero value exports high quality
+// rotating clips from DefaultExportParallel cameras at a time.
+type ExportOptions struct {
+	// Clip is used for every camera. Clip.Progress is ignored; use Progress.
+	Clip ClipOptions
+	// Parallel is how many cameras are downloaded at once. Default: DefaultExportParallel.
+	Parallel int
+	// Progress is called every time clip data is written, with the camera it's for.
+	// It's called from several goroutines at once.
+	Progress func(cameraID string, progress ClipProgress)
+}
+
+// ExportManifest describes the files written by ExportClips. It's also written
+// to ExportManifestFile in the export directory.
+type ExportManifest struct {
+	Start   time.Time       `json:"start"`
+	End     time.Time       `json:"end"`
+	Type    ClipType        `json:"type"`
+	Channel int             `json:"channel"`
+	Lens    int             `json:"lens"`
+	FPS     int             `json:"fps,omitempty"`
+	Created time.Time       `json:"created"`
+	Clips   []*ExportedClip `json:"clips"` // in the order the cameras were requested.
+}
+
+// ExportedClip is one camera's clip in an ExportManifest.
+type ExportedClip struct {
+	CameraID   string `json:"cameraId"`
+	CameraName string `json:"cameraName"`
+	File       string `json:"file,omitempty"` // relative to the export directory.
+	Bytes      int64  `json:"bytes"`
+	SHA256     string `json:"sha256,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExportClips downloads the same time window from several cameras at once, into one
+// file per camera in dir, and writes a manifest of the files to ExportManifestFile.
+// Prepare requests are sent one at a time, because the prepare API is easily
+// overloaded (see StreamClip); the downloads run in parallel. A camera that fails
+// does not stop the others: its error is in the manifest, and all of the camera
+// errors are joined and returned with the manifest. dir is created if needed. Repeated
+// camera IDs are exported once. Clips are not split, so opts.Clip.MaxSegment must not
+// split the time window; ErrClipSegmented is returned if it does.
+func (u *Unifi) ExportClips(
+	cameraIDs []string, start, end time.Time, dir string, opts *ExportOptions,
+) (*ExportManifest, error) {
+	return u.ExportClipsCtx(context.Background(), cameraIDs, start, end, dir, opts)
+}
+
+// ExportClipsCtx is the same as ExportClips, but uses the provided context.
+func (u *Unifi) ExportClipsCtx(
+	ctx context.Context, cameraIDs []string, start, end time.Time, dir string, opts *ExportOptions,
+) (*ExportManifest, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	if segments := clipSegments(start, end, opts.Clip.MaxSegment); len(segments) > 1 {
+		return nil, fmt.Errorf("%w: ExportClips writes one file per camera", ErrClipSegmented)
+	}
+
+	// Two workers must never write the same file.
+	unique := make([]string, 0, len(cameraIDs))
+	for _, id := range cameraIDs {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	cameraIDs = unique
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gomnd
+		return nil, fmt.Errorf("creating export directory: %w", err)
+	}
+
+	cameras, err := u.GetCamerasCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(cameras))
+	for _, camera := range cameras {
+		names[camera.ID] = camera.Name
+	}
+
+	clip := opts.Clip
+	clip.Progress = nil
+	clip.prepare = &sync.Mutex{}
+
+	if clip.Type == "" {
+		clip.Type = ClipTypeRotating
+	}
+
+	manifest := &ExportManifest{
+		Start:   start,
+		End:     end,
+		Type:    clip.Type,
+		Channel: clip.Channel,
+		Lens:    clip.Lens,
+		Created: time.Now(),
+		Clips:   make([]*ExportedClip, len(cameraIDs)),
+	}
+
+	if clip.Type == ClipTypeTimelapse {
+		manifest.FPS = clip.FPS
+	}
+
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = DefaultExportParallel
+	}
+
+	errs := make([]error, len(cameraIDs))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(parallel, len(cameraIDs)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobs {
+				manifest.Clips[idx] = &ExportedClip{CameraID: cameraIDs[idx]}
+				errs[idx] = u.exportClip(ctx, manifest.Clips[idx], names, start, end, dir, clip, opts.Progress)
+			}
+		}()
+	}
+
+	for idx := range cameraIDs {
+		jobs <- idx
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err := writeManifest(dir, manifest); err != nil {
+		errs = append(errs, err)
+	}
+
+	return manifest, errors.Join(errs...)
+}
+
+// exportClip downloads one camera's clip to a file in dir, and fills in the clip's camera name
+// and file details. The clip is written to a .part file that is renamed when it's complete.
+func (u *Unifi) exportClip(
+	ctx context.Context, clip *ExportedClip, names map[string]string, start, end time.Time,
+	dir string, opts ClipOptions, progress func(string, ClipProgress),
+) (err error) {
+	defer func() {
+		if err != nil {
+			clip.Error = err.Error()
+			err = fmt.Errorf("camera %s: %w", clip.CameraID, err)
+		}
+	}()
+
+	name, ok := names[clip.CameraID]
+	if !ok {
+		return fmt.Errorf("%w: camera %s", ErrNotFound, clip.CameraID)
+	}
+
+	clip.CameraName = name
+
+	if progress != nil {
+		opts.Progress = func(p ClipProgress) { progress(clip.CameraID, p) }
+	}
+
+	// The name has the channel and lens, so exports with other options don't overwrite it.
+	file := opts.values(clip.CameraID, start, end).Get("filename")
+	path := filepath.Join(dir, file)
+
+	f, err := os.Create(path + ".part")
+	if err != nil {
+		return fmt.Errorf("creating clip file: %w", err)
+	}
+
+	hash := sha256.New()
+	err = u.StreamClip(ctx, clip.CameraID, start, end, io.MultiWriter(f, hash), &opts)
+
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("closing clip file: %w", closeErr)
+	}
+
+	if err == nil {
+		if renameErr := os.Rename(path+".part", path); renameErr != nil {
+			err = fmt.Errorf("renaming clip file: %w", renameErr)
+		}
+	}
+
+	if err != nil {
+		os.Remove(path + ".part")
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading clip file: %w", err)
+	}
+
+	clip.File = file
+	clip.Bytes = info.Size()
+	clip.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+func writeManifest(dir string, manifest *ExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ExportManifestFile), append(data, '\n'), 0o644); err != nil { //nolint:gomnd,gosec
+		return fmt.Errorf("writing export manifest: %w", err)
+	}
+
+	return nil
+}
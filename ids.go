@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -59,24 +60,25 @@ type IDS struct {
 // Events between start and end are returned. End defaults to time.Now().
 // nolint: revive
 func (u *Unifi) GetIDS(sites []*Site, timeRange ...time.Time) ([]*IDS, error) {
-	data := []*IDS{}
-
-	for _, site := range sites {
-		response, err := u.GetIDSSite(site, timeRange...)
-		if err != nil {
-			return data, err
-		}
-
-		data = append(data, response...)
-	}
+	return u.GetIDSCtx(context.Background(), sites, timeRange...)
+}
 
-	return data, nil
+// GetIDSCtx is the same as GetIDS, but uses the provided context.
+func (u *Unifi) GetIDSCtx(ctx context.Context, sites []*Site, timeRange ...time.Time) ([]*IDS, error) {
+	return eachSite(ctx, u, sites, func(ctx context.Context, site *Site) ([]*IDS, error) {
+		return u.GetIDSSiteCtx(ctx, site, timeRange...)
+	})
 }
 
 // GetIDSSite retrieves the Intrusion Detection System Data for a single Site.
 // timeRange may have a length of 0, 1 or 2. The first time is Start, the second is End.
 // Events between start and end are returned. End defaults to time.Now().
 func (u *Unifi) GetIDSSite(site *Site, timeRange ...time.Time) ([]*IDS, error) {
+	return u.GetIDSSiteCtx(context.Background(), site, timeRange...)
+}
+
+// GetIDSSiteCtx is the same as GetIDSSite, but uses the provided context.
+func (u *Unifi) GetIDSSiteCtx(ctx context.Context, site *Site, timeRange ...time.Time) ([]*IDS, error) {
 	if site == nil || site.Name == "" {
 		return nil, ErrNoSiteProvided
 	}
@@ -92,7 +94,7 @@ func (u *Unifi) GetIDSSite(site *Site, timeRange ...time.Time) ([]*IDS, error) {
 
 	if params, err := makeEventParams(timeRange...); err != nil {
 		return ids.Data, err
-	} else if err = u.GetData(path, &ids, params); err != nil {
+	} else if err = u.GetDataCtx(ctx, path, &ids, params); err != nil {
 		return ids.Data, err
 	}
 
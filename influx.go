@@ -0,0 +1,411 @@
+package unifi
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is a single InfluxDB data point. Create them with the Points methods
+// attached to the device, client, site, DPI and event types, and write them
+// with WriteLineProtocol.
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// String returns the point in InfluxDB line protocol with a nanosecond timestamp.
+// Empty tags are left out, as InfluxDB does not allow them. A point needs at least
+// one field, so String returns an empty string if none of the fields can be written.
+func (p *Point) String() string {
+	fields := make([]string, 0, len(p.Fields))
+
+	for _, key := range sortedKeys(p.Fields) {
+		if value, ok := fieldValue(p.Fields[key]); ok {
+			fields = append(fields, tagEscaper.Replace(key)+"="+value)
+		}
+	}
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString(lineEscaper.Replace(p.Measurement))
+
+	for _, key := range sortedKeys(p.Tags) {
+		if p.Tags[key] == "" {
+			continue
+		}
+
+		buf.WriteString("," + tagEscaper.Replace(key) + "=" + tagEscaper.Replace(p.Tags[key]))
+	}
+
+	buf.WriteString(" " + strings.Join(fields, ","))
+
+	if !p.Time.IsZero() {
+		buf.WriteString(" " + strconv.FormatInt(p.Time.UnixNano(), 10))
+	}
+
+	return buf.String()
+}
+
+// WriteLineProtocol writes the points to w in InfluxDB line protocol, one per line.
+// Points without a writable field are skipped. The output can be posted to the InfluxDB write API.
+func WriteLineProtocol(w io.Writer, points []*Point) error {
+	for _, point := range points {
+		if point == nil {
+			continue
+		}
+
+		line := point.String()
+		if line == "" {
+			continue
+		}
+
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return fmt.Errorf("writing line protocol: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// nolint: gochecknoglobals
+var (
+	lineEscaper   = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	tagEscaper    = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+	stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// fieldValue formats a field value for line protocol. Unsupported values, NaN and Inf return false.
+func fieldValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case string:
+		return `"` + stringEscaper.Replace(v) + `"`, true
+	default:
+		return "", false
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// flexFields collects the FlexInt, FlexTemp and FlexBool values from a struct
+// into a map of fields keyed by their json names. Nested structs are included
+// with their json name as a prefix; slices and maps are skipped.
+func flexFields(v interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	addFlexFields(fields, "", reflect.ValueOf(v))
+
+	return fields
+}
+
+func addFlexFields(fields map[string]interface{}, prefix string, value reflect.Value) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		} else if name == "" && !field.Anonymous {
+			name = strings.ToLower(field.Name)
+		}
+
+		switch val := value.Field(i).Interface().(type) {
+		case FlexInt:
+			fields[prefix+name] = val.Val
+		case FlexTemp:
+			fields[prefix+name] = val.Val
+		case FlexBool:
+			fields[prefix+name] = val.Val
+		case *FlexInt, *FlexTemp, *FlexBool, time.Time:
+			// Pointers are optional values and times are not fields.
+		default:
+			if field.Anonymous && name == "" {
+				addFlexFields(fields, prefix, value.Field(i))
+			} else {
+				addFlexFields(fields, prefix+name+"_", value.Field(i))
+			}
+		}
+	}
+}
+
+// devicePoint creates the point for a device with the standard tags.
+func devicePoint(measurement string, dev interface{}, now time.Time, site, source, name, mac, model, typ string) *Point {
+	return &Point{
+		Measurement: measurement,
+		Tags: map[string]string{
+			"site":   site,
+			"source": source,
+			"name":   name,
+			"mac":    mac,
+			"model":  model,
+			"type":   typ,
+		},
+		Fields: flexFields(dev),
+		Time:   now,
+	}
+}
+
+// Points returns the InfluxDB data point for the access point, timestamped now.
+func (u *UAP) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("uap", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data point for the switch, timestamped now.
+func (u *USW) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("usw", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data point for the security gateway, timestamped now.
+func (u *USG) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("usg", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data point for the dream machine, timestamped now.
+func (u *UDM) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("udm", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data point for the next-gen gateway, timestamped now.
+func (u *UXG) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("uxg", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data point for the power distribution unit, timestamped now.
+func (u *PDU) Points(now time.Time) []*Point {
+	return []*Point{devicePoint("pdu", u, now, u.SiteName, u.SourceName, u.Name, u.Mac, u.Model, u.Type)}
+}
+
+// Points returns the InfluxDB data points for all of the devices, timestamped now.
+func (d *Devices) Points(now time.Time) []*Point {
+	points := []*Point{}
+
+	for _, dev := range d.UAPs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	for _, dev := range d.USWs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	for _, dev := range d.USGs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	for _, dev := range d.UDMs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	for _, dev := range d.UXGs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	for _, dev := range d.PDUs {
+		points = append(points, dev.Points(now)...)
+	}
+
+	return points
+}
+
+// Points returns the InfluxDB data point for the client, timestamped now.
+func (c *Client) Points(now time.Time) []*Point {
+	return []*Point{{
+		Measurement: "client",
+		Tags: map[string]string{
+			"site":   c.SiteName,
+			"source": c.SourceName,
+			"name":   c.Name,
+			"mac":    c.Mac,
+			"oui":    c.Oui,
+		},
+		Fields: flexFields(c),
+		Time:   now,
+	}}
+}
+
+// Points returns an InfluxDB data point for each subsystem in the site's health data, timestamped now.
+func (s *Site) Points(now time.Time) []*Point {
+	points := make([]*Point, 0, len(s.Health))
+
+	for i := range s.Health {
+		fields := flexFields(&s.Health[i])
+		fields["status"] = s.Health[i].Status
+
+		points = append(points, &Point{
+			Measurement: "site_health",
+			Tags: map[string]string{
+				"site":      s.SiteName,
+				"source":    s.SourceName,
+				"name":      s.Name,
+				"subsystem": s.Health[i].Subsystem,
+			},
+			Fields: fields,
+			Time:   now,
+		})
+	}
+
+	return points
+}
+
+// Points returns an InfluxDB data point for each application and category in the DPI table,
+// timestamped now. The name tag is the client or site the table belongs to.
+func (d *DPITable) Points(now time.Time) []*Point {
+	points := make([]*Point, 0, len(d.ByApp)+len(d.ByCat))
+
+	for i := range d.ByApp {
+		point := d.point("dpi_application", &d.ByApp[i], now)
+		point.Tags["application"] = DPIApps.GetApp(int(d.ByApp[i].Cat.Val), int(d.ByApp[i].App.Val))
+		points = append(points, point)
+	}
+
+	for i := range d.ByCat {
+		points = append(points, d.point("dpi_category", &d.ByCat[i], now))
+	}
+
+	return points
+}
+
+func (d *DPITable) point(measurement string, data *DPIData, now time.Time) *Point {
+	fields := flexFields(data)
+	delete(fields, "app")
+	delete(fields, "cat")
+
+	return &Point{
+		Measurement: measurement,
+		Tags: map[string]string{
+			"site":     d.SiteName,
+			"source":   d.SourceName,
+			"name":     d.Name,
+			"mac":      d.MAC,
+			"category": DPICats.Get(int(data.Cat.Val)),
+		},
+		Fields: fields,
+		Time:   now,
+	}
+}
+
+// Points returns the InfluxDB data point for the event, timestamped with the event's Datetime,
+// or now if it has none.
+func (e *Event) Points(now time.Time) []*Point {
+	fields := flexFields(e)
+	fields["msg"] = e.Msg
+
+	return []*Point{{
+		Measurement: "event",
+		Tags: map[string]string{
+			"site":      e.SiteName,
+			"source":    e.SourceName,
+			"key":       e.Key,
+			"subsystem": e.Subsystem,
+			"catname":   e.Catname.Val,
+		},
+		Fields: fields,
+		Time:   eventTime(e.Datetime, now),
+	}}
+}
+
+// Points returns the InfluxDB data point for the IDS event, timestamped with the event's Datetime,
+// or now if it has none.
+func (i *IDS) Points(now time.Time) []*Point {
+	fields := flexFields(i)
+	fields["msg"] = i.Msg
+
+	return []*Point{{
+		Measurement: "ids",
+		Tags: map[string]string{
+			"site":      i.SiteName,
+			"source":    i.SourceName,
+			"key":       i.Key,
+			"subsystem": i.Subsystem,
+			"catname":   i.Catname.Val,
+		},
+		Fields: fields,
+		Time:   eventTime(i.Datetime, now),
+	}}
+}
+
+// Points returns the InfluxDB data point for the alarm, timestamped with the alarm's Datetime,
+// or now if it has none.
+func (a *Alarm) Points(now time.Time) []*Point {
+	fields := flexFields(a)
+	fields["msg"] = a.Msg
+
+	return []*Point{{
+		Measurement: "alarm",
+		Tags: map[string]string{
+			"site":      a.SiteName,
+			"source":    a.SourceName,
+			"key":       a.Key,
+			"subsystem": a.Subsystem,
+			"catname":   a.Catname.Val,
+		},
+		Fields: fields,
+		Time:   eventTime(a.Datetime, now),
+	}}
+}
+
+// Points returns the InfluxDB data point for the anomaly, timestamped with the anomaly's Datetime,
+// or now if it has none.
+func (a *Anomaly) Points(now time.Time) []*Point {
+	return []*Point{{
+		Measurement: "anomaly",
+		Tags: map[string]string{
+			"site":   a.SiteName,
+			"source": a.SourceName,
+			"mac":    a.DeviceMAC,
+		},
+		Fields: map[string]interface{}{"anomaly": a.Anomaly},
+		Time:   eventTime(a.Datetime, now),
+	}}
+}
+
+// eventTime returns the time an event happened, or now if the event has no time.
+func eventTime(datetime, now time.Time) time.Time {
+	if datetime.IsZero() {
+		return now
+	}
+
+	return datetime
+}
@@ -0,0 +1,61 @@
+package unifi // nolint: testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointString(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	point := &Point{
+		Measurement: "my measure",
+		Tags:        map[string]string{"site": "Default (default)", "name": "a,b=c", "empty": ""},
+		Fields: map[string]interface{}{
+			"msg": `say "hi"`, "bytes": 12.5, "count": 3, "up": true, "skip": struct{}{},
+		},
+		Time: time.Unix(1, 5),
+	}
+
+	a.Equal(`my\ measure,name=a\,b\=c,site=Default\ (default) bytes=12.5,count=3i,msg="say \"hi\"",up=true 1000000005`,
+		point.String())
+
+	var buf bytes.Buffer
+
+	invalid := &Point{Measurement: "invalid", Fields: map[string]interface{}{"nan": math.NaN(), "skip": struct{}{}}}
+	a.Empty(invalid.String(), "a point without a valid field is not a valid line")
+
+	a.NoError(WriteLineProtocol(&buf, []*Point{point, {Measurement: "no_fields"}, invalid, nil}))
+	a.Equal(point.String()+"\n", buf.String(), "points without fields must be skipped")
+}
+
+func TestUAPPoints(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	uap := &UAP{SiteName: "site1", SourceName: "https://unifi"}
+	a.NoError(json.Unmarshal(uapSample, uap))
+
+	now := time.Now()
+	points := uap.Points(now)
+	a.Len(points, 1)
+	a.Equal("uap", points[0].Measurement)
+	a.Equal(now, points[0].Time)
+	a.Equal(map[string]string{
+		"site": "site1", "source": "https://unifi", "name": uap.Name, "mac": uap.Mac, "model": uap.Model, "type": "uap",
+	}, points[0].Tags)
+	a.Equal(true, points[0].Fields["adopted"])
+	a.Equal(uap.Stat.RxPackets.Val, points[0].Fields["stat_rx_packets"], "embedded stats must be prefixed")
+	a.NotContains(points[0].Fields, "antenna_table_id", "slices must be skipped")
+
+	alarm := &Alarm{Msg: "alarm!", Datetime: time.Unix(100, 0)}
+	a.Equal(time.Unix(100, 0), alarm.Points(now)[0].Time, "events must use their own timestamp")
+	a.Equal("alarm!", alarm.Points(now)[0].Fields["msg"])
+	a.Equal(now, (&Anomaly{Anomaly: "x"}).Points(now)[0].Time, "events without a time must use now")
+}
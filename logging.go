@@ -0,0 +1,185 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// siteKey is the context key used to attach the polled site to log lines.
+type siteKey struct{}
+
+// withSite returns a context that adds the site name to request log lines.
+func withSite(ctx context.Context, site *Site) context.Context {
+	if site == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, siteKey{}, site)
+}
+
+// setupLogs makes sure all three loggers are usable. When Config.Slog is provided,
+// missing printf loggers write to it. Otherwise, structured log lines are
+// formatted and written to the ErrorLog and DebugLog funcs.
+func (u *Unifi) setupLogs() {
+	if u.Slog != nil {
+		u.log = u.Slog
+
+		if u.ErrorLog == nil {
+			u.ErrorLog = func(msg string, v ...interface{}) { u.Slog.Error(fmt.Sprintf(msg, v...)) }
+		}
+
+		if u.DebugLog == nil {
+			u.DebugLog = func(msg string, v ...interface{}) { u.Slog.Debug(fmt.Sprintf(msg, v...)) }
+		}
+
+		return
+	}
+
+	handler := &logFuncHandler{errorOn: u.ErrorLog != nil, debugOn: u.DebugLog != nil}
+
+	if u.ErrorLog == nil {
+		u.ErrorLog = discardLogs
+	}
+
+	if u.DebugLog == nil {
+		u.DebugLog = discardLogs
+	}
+
+	handler.errorLog, handler.debugLog = u.ErrorLog, u.DebugLog
+	u.log = slog.New(handler)
+}
+
+// discardLog is used when a Unifi was not created by NewUnifi, and has no logger.
+var discardLog = slog.New(&logFuncHandler{errorLog: discardLogs, debugLog: discardLogs})
+
+// logger returns the structured logger. It never returns nil.
+func (u *Unifi) logger() *slog.Logger {
+	if u.log == nil {
+		return discardLog
+	}
+
+	return u.log
+}
+
+// logRequest writes a structured line for a request. Failed requests
+// are logged as warnings with the error.
+func (u *Unifi) logRequest(ctx context.Context, start time.Time, method, path string, status, size int, err error) {
+	level, msg := slog.LevelDebug, "Requested"
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Int("bytes", size),
+		slog.Duration("elapsed", time.Since(start).Round(time.Millisecond)),
+	}
+
+	if site, ok := ctx.Value(siteKey{}).(*Site); ok {
+		attrs = append(attrs, slog.String("site", site.SiteName))
+	}
+
+	if err != nil {
+		level, msg = slog.LevelWarn, "Request failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	u.logger().LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logFuncHandler is a slog.Handler that writes to the printf style Logger funcs.
+// Warnings and errors go to errorLog, everything else goes to debugLog.
+// Attributes are appended to the message as key=value pairs.
+type logFuncHandler struct {
+	errorLog Logger
+	debugLog Logger
+	errorOn  bool
+	debugOn  bool
+	attrs    string // preformatted attributes from WithAttrs.
+	group    string // prefix for attribute keys from WithGroup.
+}
+
+// Enabled satisfies the slog.Handler interface.
+func (h *logFuncHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level >= slog.LevelWarn {
+		return h.errorOn
+	}
+
+	return h.debugOn
+}
+
+// Handle satisfies the slog.Handler interface.
+func (h *logFuncHandler) Handle(_ context.Context, record slog.Record) error {
+	var buf strings.Builder
+
+	buf.WriteString(record.Message)
+	buf.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&buf, h.group, attr)
+
+		return true
+	})
+
+	if record.Level >= slog.LevelWarn {
+		h.errorLog("%s", buf.String())
+	} else {
+		h.debugLog("%s", buf.String())
+	}
+
+	return nil
+}
+
+// WithAttrs satisfies the slog.Handler interface.
+func (h *logFuncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var buf strings.Builder
+
+	for _, attr := range attrs {
+		appendAttr(&buf, h.group, attr)
+	}
+
+	handler := *h
+	handler.attrs += buf.String()
+
+	return &handler
+}
+
+// WithGroup satisfies the slog.Handler interface.
+func (h *logFuncHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	handler := *h
+	handler.group += name + "."
+
+	return &handler
+}
+
+// appendAttr writes an attribute as " key=value", quoting values that need it.
+func appendAttr(buf *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, a := range attr.Value.Group() {
+			appendAttr(buf, prefix, a)
+		}
+
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = strconv.Quote(value)
+	}
+
+	buf.WriteString(" " + prefix + attr.Key + "=" + value)
+}
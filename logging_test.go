@@ -0,0 +1,101 @@
+package unifi // nolint: testpackage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlog(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"type":"uap","model":"U7PG2","name":1}]}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	u, err := NewUnifi(&Config{URL: srv.URL, APIKey: "key", Slog: logger})
+	a.NoError(err)
+
+	_, err = u.GetDevices([]*Site{{Name: "default", SiteName: "Default (default)"}})
+	a.NoError(err)
+
+	logs := buf.String()
+	a.Contains(logs, `msg=Requested method=GET path=`+APIPrefixNew+`/api/s/default/stat/device`+
+		` status=200 bytes=`, "request lines must have attributes")
+	a.Contains(logs, `site="Default (default)"`, "the polled site must be added to request lines")
+	a.Contains(logs, `level=ERROR msg="json.Unmarshal failed" into=uap site="Default (default)" type=uap model=U7PG2`,
+		"parse failures must have the device type and model")
+	a.Contains(logs, `level=DEBUG msg="Requesting http`, "printf logs must be written to Slog")
+
+	buf.Reset()
+	a.NoError(u.GetData(APISiteList, &struct{}{}, `{"a":"b"}`))
+	a.Contains(buf.String(), `msg=Requested method=POST path=`, "requests with params must be logged as a POST")
+}
+
+func TestSlogFailedRequest(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	u, err := NewUnifi(&Config{URL: srv.URL, APIKey: "key", Slog: logger})
+	a.NoError(err)
+
+	a.Error(u.GetData("/api/s/default/stat/device?x=1", &struct{}{}))
+	a.Contains(buf.String(), `level=WARN msg="Request failed" method=GET path=/api/s/default/stat/device status=404 bytes=0`,
+		"failed requests must be logged with their status, and without the query")
+}
+
+func TestNoLogger(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"type":"uap","model":"U7PG2","name":1}]}`))
+	}))
+	defer srv.Close()
+
+	u := &Unifi{Client: srv.Client(), Config: &Config{URL: srv.URL, DebugLog: discardLogs}}
+
+	assert.NoError(t, u.GetData(APISiteList, &struct{}{}), "a Unifi without a logger must not panic")
+
+	_, err := u.GetDevices([]*Site{{Name: "default"}})
+	assert.NoError(t, err)
+}
+
+func TestLogFuncHandler(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var debug, errs []string
+
+	u := newUnifi(&Config{
+		DebugLog: func(msg string, v ...interface{}) { debug = append(debug, fmt.Sprintf(msg, v...)) },
+		ErrorLog: func(msg string, v ...interface{}) { errs = append(errs, fmt.Sprintf(msg, v...)) },
+	}, nil)
+
+	u.log.With("site", "default").WithGroup("dev").Error("failed", "model", "U7 PG2")
+	u.log.Debug("Requested", "status", 200)
+	a.Equal([]string{`failed site=default dev.model="U7 PG2"`}, errs)
+	a.Equal([]string{`Requested status=200`}, debug)
+
+	u = newUnifi(&Config{}, nil)
+	a.False(u.log.Enabled(context.Background(), slog.LevelError), "no logger means no formatting")
+	a.NotNil(u.DebugLog, "loggers must never be nil")
+}
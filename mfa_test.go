@@ -0,0 +1,59 @@
+package unifi // nolint: testpackage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	// RFC 6238 appendix B test vectors (SHA1), truncated to 6 digits.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	for unix, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := totpCode(secret, time.Unix(unix, 0))
+		a.NoError(err)
+		a.Equal(want, code, "wrong code at %d", unix)
+	}
+
+	_, err := totpCode("not base32!", time.Now())
+	a.ErrorIs(err, ErrInvalidTOTPSecret)
+}
+
+func TestLoginMFA(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var login map[string]string
+
+		a.NoError(json.NewDecoder(r.Body).Decode(&login), "login must be valid json")
+
+		if login["ubic_2fa_token"] != "123456" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"meta":{"rc":"error","msg":"api.err.Ubic2faTokenRequired"},"data":[]}`))
+
+			return
+		}
+
+		a.Equal(`pa"ss`, login["password"], "credentials must be json encoded")
+	}))
+	defer srv.Close()
+
+	u := newUnifi(&Config{URL: srv.URL, User: "admin", Pass: `pa"ss`}, nil)
+	a.ErrorIs(u.Login(), ErrMFARequired, "a missing token source must be reported")
+
+	u.MFAToken = func() (string, error) { return "123456", nil }
+	a.NoError(u.Login(), "the login must be resubmitted with the token")
+}
@@ -0,0 +1,482 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/secure-passage/unifi"
+	upstream "github.com/unpoller/unifi"
+)
+
+// MockUnifiCtx is a mock of the context methods in unifi.UnifiClientCtx. Getters return
+// fake data, and the methods that change something succeed without doing anything.
+type MockUnifiCtx struct {
+	*unifi.Config
+}
+
+// ensure MockUnifiCtx implements the interface fully, this will fail to compile otherwise
+var _ unifi.UnifiClientCtx = &MockUnifiCtx{}
+
+func NewMockUnifiCtx() *MockUnifiCtx {
+	return &MockUnifiCtx{}
+}
+
+// fakeList returns numItemsMocked fake items.
+func fakeList[T any]() ([]*T, error) {
+	items := make([]*T, numItemsMocked)
+
+	for i := 0; i < numItemsMocked; i++ {
+		item, err := fakeItem[T]()
+		if err != nil {
+			return items, err
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// fakeItem returns one fake item.
+func fakeItem[T any]() (*T, error) {
+	var item T
+
+	if err := gofakeit.Struct(&item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// fakeMirrorList returns numItemsMocked fake items made by fakeMirror.
+func fakeMirrorList[U, T any]() ([]*T, error) {
+	items := make([]*T, numItemsMocked)
+
+	for i := 0; i < numItemsMocked; i++ {
+		item, err := fakeMirror[U, T]()
+		if err != nil {
+			return items, err
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// fakeMirror fakes U, the upstream copy of T, and converts it to T. The device types
+// can't be faked directly in this package: gofakeit's custom functions are global,
+// and the upstream package imported by mock_client.go registers the same names.
+func fakeMirror[U, T any]() (*T, error) {
+	mirror, err := fakeItem[U]()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	var item T
+
+	return &item, json.Unmarshal(data, &item)
+}
+
+// GetAlarmsCtx returns Alarms for a list of Sites.
+func (m *MockUnifiCtx) GetAlarmsCtx(_ context.Context, _ []*unifi.Site) ([]*unifi.Alarm, error) {
+	return fakeList[unifi.Alarm]()
+}
+
+// GetAlarmsSiteCtx retreives the Alarms for a single Site.
+func (m *MockUnifiCtx) GetAlarmsSiteCtx(_ context.Context, _ *unifi.Site) ([]*unifi.Alarm, error) {
+	return fakeList[unifi.Alarm]()
+}
+
+// GetAnomaliesCtx returns Anomalies for a list of Sites.
+func (m *MockUnifiCtx) GetAnomaliesCtx(_ context.Context, _ []*unifi.Site, _ ...time.Time) ([]*unifi.Anomaly, error) {
+	return fakeList[unifi.Anomaly]()
+}
+
+// GetAnomaliesSiteCtx retreives the Anomalies for a single Site.
+func (m *MockUnifiCtx) GetAnomaliesSiteCtx(_ context.Context, _ *unifi.Site, _ ...time.Time) ([]*unifi.Anomaly, error) {
+	return fakeList[unifi.Anomaly]()
+}
+
+// GetClientsCtx returns a list of Clients.
+func (m *MockUnifiCtx) GetClientsCtx(_ context.Context, _ []*unifi.Site) ([]*unifi.Client, error) {
+	return fakeList[unifi.Client]()
+}
+
+// GetClientsDPICtx returns a list of Client DPI data.
+func (m *MockUnifiCtx) GetClientsDPICtx(_ context.Context, _ []*unifi.Site) ([]*unifi.DPITable, error) {
+	return fakeList[unifi.DPITable]()
+}
+
+// GetDevicesCtx returns every device.
+func (m *MockUnifiCtx) GetDevicesCtx(_ context.Context, _ []*unifi.Site) (*unifi.Devices, error) {
+	return fakeMirror[upstream.Devices, unifi.Devices]()
+}
+
+// GetUSWsCtx returns the switches for a site.
+func (m *MockUnifiCtx) GetUSWsCtx(_ context.Context, _ *unifi.Site) ([]*unifi.USW, error) {
+	return fakeMirrorList[upstream.USW, unifi.USW]()
+}
+
+// GetUAPsCtx returns the access points for a site.
+func (m *MockUnifiCtx) GetUAPsCtx(_ context.Context, _ *unifi.Site) ([]*unifi.UAP, error) {
+	return fakeMirrorList[upstream.UAP, unifi.UAP]()
+}
+
+// GetUDMsCtx returns the dream machines for a site.
+func (m *MockUnifiCtx) GetUDMsCtx(_ context.Context, _ *unifi.Site) ([]*unifi.UDM, error) {
+	return fakeMirrorList[upstream.UDM, unifi.UDM]()
+}
+
+// GetUXGsCtx returns the next-gen gateways for a site.
+func (m *MockUnifiCtx) GetUXGsCtx(_ context.Context, _ *unifi.Site) ([]*unifi.UXG, error) {
+	return fakeMirrorList[upstream.UXG, unifi.UXG]()
+}
+
+// GetUSGsCtx returns the security gateways for a site.
+func (m *MockUnifiCtx) GetUSGsCtx(_ context.Context, _ *unifi.Site) ([]*unifi.USG, error) {
+	return fakeMirrorList[upstream.USG, unifi.USG]()
+}
+
+// GetEventsCtx returns the events for a list of sites.
+func (m *MockUnifiCtx) GetEventsCtx(_ context.Context, _ []*unifi.Site, _ time.Duration) ([]*unifi.Event, error) {
+	return fakeList[unifi.Event]()
+}
+
+// GetSiteEventsCtx returns the events for a site.
+func (m *MockUnifiCtx) GetSiteEventsCtx(_ context.Context, _ *unifi.Site, _ time.Duration) ([]*unifi.Event, error) {
+	return fakeList[unifi.Event]()
+}
+
+// GetIDSCtx returns the intrusion detection events for a list of sites.
+func (m *MockUnifiCtx) GetIDSCtx(_ context.Context, _ []*unifi.Site, _ ...time.Time) ([]*unifi.IDS, error) {
+	return fakeList[unifi.IDS]()
+}
+
+// GetIDSSiteCtx returns the intrusion detection events for a site.
+func (m *MockUnifiCtx) GetIDSSiteCtx(_ context.Context, _ *unifi.Site, _ ...time.Time) ([]*unifi.IDS, error) {
+	return fakeList[unifi.IDS]()
+}
+
+// GetNetworksCtx returns the networks for a list of sites.
+func (m *MockUnifiCtx) GetNetworksCtx(_ context.Context, _ []*unifi.Site) ([]unifi.Network, error) {
+	networks, err := fakeList[unifi.Network]()
+	results := make([]unifi.Network, 0, len(networks))
+
+	for _, network := range networks {
+		if network != nil {
+			results = append(results, *network)
+		}
+	}
+
+	return results, err
+}
+
+// GetSitesCtx returns the sites.
+func (m *MockUnifiCtx) GetSitesCtx(_ context.Context) ([]*unifi.Site, error) {
+	return fakeList[unifi.Site]()
+}
+
+// GetSiteDPICtx returns the DPI data for a list of sites.
+func (m *MockUnifiCtx) GetSiteDPICtx(_ context.Context, _ []*unifi.Site) ([]*unifi.DPITable, error) {
+	return fakeList[unifi.DPITable]()
+}
+
+// GetRogueAPsCtx returns the rogue access points for a list of sites.
+func (m *MockUnifiCtx) GetRogueAPsCtx(_ context.Context, _ []*unifi.Site) ([]*unifi.RogueAP, error) {
+	return fakeList[unifi.RogueAP]()
+}
+
+// GetRogueAPsSiteCtx returns the rogue access points for a site.
+func (m *MockUnifiCtx) GetRogueAPsSiteCtx(_ context.Context, _ *unifi.Site) ([]*unifi.RogueAP, error) {
+	return fakeList[unifi.RogueAP]()
+}
+
+// LoginCtx does nothing.
+func (m *MockUnifiCtx) LoginCtx(_ context.Context) error {
+	return nil
+}
+
+// LogoutCtx does nothing.
+func (m *MockUnifiCtx) LogoutCtx(_ context.Context) error {
+	return nil
+}
+
+// GetServerDataCtx returns the server status.
+func (m *MockUnifiCtx) GetServerDataCtx(_ context.Context) (*unifi.ServerStatus, error) {
+	return fakeItem[unifi.ServerStatus]()
+}
+
+// GetUsersCtx returns the users for a list of sites.
+func (m *MockUnifiCtx) GetUsersCtx(_ context.Context, _ []*unifi.Site, _ int) ([]*unifi.User, error) {
+	return fakeList[unifi.User]()
+}
+
+// GetCamerasCtx returns the Protect cameras.
+func (m *MockUnifiCtx) GetCamerasCtx(_ context.Context) ([]*unifi.Camera, error) {
+	return fakeList[unifi.Camera]()
+}
+
+// GetCameraByIDCtx returns a camera with the provided ID.
+func (m *MockUnifiCtx) GetCameraByIDCtx(_ context.Context, value string) (*unifi.Camera, error) {
+	camera, err := fakeItem[unifi.Camera]()
+	if camera != nil {
+		camera.ID = value
+	}
+
+	return camera, err
+}
+
+// GetCameraByNameCtx returns a camera with the provided name.
+func (m *MockUnifiCtx) GetCameraByNameCtx(_ context.Context, value string) (*unifi.Camera, error) {
+	camera, err := fakeItem[unifi.Camera]()
+	if camera != nil {
+		camera.Name = value
+	}
+
+	return camera, err
+}
+
+// GetClipBytesCtx returns a fake clip.
+func (m *MockUnifiCtx) GetClipBytesCtx(_ context.Context, _ string, _, _ time.Time) ([]byte, error) {
+	return []byte(gofakeit.LetterN(numItemsMocked)), nil
+}
+
+// StreamClip writes a fake clip to w.
+func (m *MockUnifiCtx) StreamClip(
+	_ context.Context, _ string, _, _ time.Time, w io.Writer, _ *unifi.ClipOptions,
+) error {
+	_, err := w.Write([]byte(gofakeit.LetterN(numItemsMocked)))
+
+	return err
+}
+
+// StreamClipSegments writes a fake clip to one segment writer.
+func (m *MockUnifiCtx) StreamClipSegments(
+	_ context.Context, _ string, start, end time.Time, create unifi.ClipSegmentWriter, _ *unifi.ClipOptions,
+) error {
+	w, err := create(1, start, end)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write([]byte(gofakeit.LetterN(numItemsMocked))); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// SubscribeProtect returns a channel that is closed when the context is done.
+func (m *MockUnifiCtx) SubscribeProtect(ctx context.Context) (<-chan *unifi.ProtectUpdate, error) {
+	updates := make(chan *unifi.ProtectUpdate)
+
+	go func() {
+		<-ctx.Done()
+		close(updates)
+	}()
+
+	return updates, nil
+}
+
+// UpdateCameraCtx returns a camera with the provided ID.
+func (m *MockUnifiCtx) UpdateCameraCtx(ctx context.Context, cameraID string, _ *unifi.CameraPatch) (*unifi.Camera, error) {
+	return m.GetCameraByIDCtx(ctx, cameraID)
+}
+
+// GetProtectBootstrapCtx returns the state of the Protect NVR.
+func (m *MockUnifiCtx) GetProtectBootstrapCtx(_ context.Context) (*unifi.Bootstrap, error) {
+	return fakeItem[unifi.Bootstrap]()
+}
+
+// GetProtectEventsCtx returns Protect events.
+func (m *MockUnifiCtx) GetProtectEventsCtx(
+	_ context.Context, _, _ time.Time, _ *unifi.ProtectEventFilter,
+) ([]*unifi.ProtectEvent, error) {
+	return fakeList[unifi.ProtectEvent]()
+}
+
+// GetCameraSnapshotCtx returns a fake image.
+func (m *MockUnifiCtx) GetCameraSnapshotCtx(_ context.Context, _ string, _, _ int, _ bool) ([]byte, error) {
+	return []byte(gofakeit.LetterN(numItemsMocked)), nil
+}
+
+// GetEventThumbnailCtx returns a fake image.
+func (m *MockUnifiCtx) GetEventThumbnailCtx(_ context.Context, _ string) ([]byte, error) {
+	return []byte(gofakeit.LetterN(numItemsMocked)), nil
+}
+
+// GetEventHeatmapCtx returns a fake image.
+func (m *MockUnifiCtx) GetEventHeatmapCtx(_ context.Context, _ string) ([]byte, error) {
+	return []byte(gofakeit.LetterN(numItemsMocked)), nil
+}
+
+// GetPTZPositionCtx returns a fake position.
+func (m *MockUnifiCtx) GetPTZPositionCtx(_ context.Context, _ *unifi.Camera) (*unifi.PTZPosition, error) {
+	return fakeItem[unifi.PTZPosition]()
+}
+
+// GetPTZPresetsCtx returns fake presets.
+func (m *MockUnifiCtx) GetPTZPresetsCtx(_ context.Context, _ *unifi.Camera) ([]*unifi.PTZPreset, error) {
+	return fakeList[unifi.PTZPreset]()
+}
+
+// GetPTZPatrolsCtx returns fake patrols.
+func (m *MockUnifiCtx) GetPTZPatrolsCtx(_ context.Context, _ *unifi.Camera) ([]*unifi.PTZPatrol, error) {
+	return fakeList[unifi.PTZPatrol]()
+}
+
+// MovePTZCtx does nothing.
+func (m *MockUnifiCtx) MovePTZCtx(_ context.Context, _ *unifi.Camera, _ unifi.PTZPosition) error {
+	return nil
+}
+
+// MovePTZToCtx does nothing.
+func (m *MockUnifiCtx) MovePTZToCtx(_ context.Context, _ *unifi.Camera, _ unifi.PTZPosition) error {
+	return nil
+}
+
+// GotoPTZPresetCtx does nothing.
+func (m *MockUnifiCtx) GotoPTZPresetCtx(_ context.Context, _ *unifi.Camera, _ int) error {
+	return nil
+}
+
+// SavePTZPresetCtx returns a preset with the provided name.
+func (m *MockUnifiCtx) SavePTZPresetCtx(_ context.Context, _ *unifi.Camera, name string) (*unifi.PTZPreset, error) {
+	preset, err := fakeItem[unifi.PTZPreset]()
+	if preset != nil {
+		preset.Name = name
+	}
+
+	return preset, err
+}
+
+// DeletePTZPresetCtx does nothing.
+func (m *MockUnifiCtx) DeletePTZPresetCtx(_ context.Context, _ *unifi.Camera, _ int) error {
+	return nil
+}
+
+// StartPTZPatrolCtx does nothing.
+func (m *MockUnifiCtx) StartPTZPatrolCtx(_ context.Context, _ *unifi.Camera, _ int) error {
+	return nil
+}
+
+// StopPTZPatrolCtx does nothing.
+func (m *MockUnifiCtx) StopPTZPatrolCtx(_ context.Context, _ *unifi.Camera) error {
+	return nil
+}
+
+// GetLightsCtx returns Protect lights.
+func (m *MockUnifiCtx) GetLightsCtx(_ context.Context) ([]*unifi.Light, error) {
+	return fakeList[unifi.Light]()
+}
+
+// GetSensorsCtx returns Protect sensors.
+func (m *MockUnifiCtx) GetSensorsCtx(_ context.Context) ([]*unifi.Sensor, error) {
+	return fakeList[unifi.Sensor]()
+}
+
+// GetChimesCtx returns Protect chimes.
+func (m *MockUnifiCtx) GetChimesCtx(_ context.Context) ([]*unifi.Chime, error) {
+	return fakeList[unifi.Chime]()
+}
+
+// GetDoorlocksCtx returns Protect doorlocks.
+func (m *MockUnifiCtx) GetDoorlocksCtx(_ context.Context) ([]*unifi.Doorlock, error) {
+	return fakeList[unifi.Doorlock]()
+}
+
+// SetLightCtx returns a light with the provided ID.
+func (m *MockUnifiCtx) SetLightCtx(_ context.Context, lightID string, on bool) (*unifi.Light, error) {
+	light, err := fakeItem[unifi.Light]()
+	if light != nil {
+		light.ID = lightID
+		light.LightOnSettings.IsLedForceOn = on
+	}
+
+	return light, err
+}
+
+// SetLightBrightnessCtx returns a light with the provided ID.
+func (m *MockUnifiCtx) SetLightBrightnessCtx(_ context.Context, lightID string, level int) (*unifi.Light, error) {
+	light, err := fakeItem[unifi.Light]()
+	if light != nil {
+		light.ID = lightID
+		light.LightDeviceSettings.LedLevel = level
+	}
+
+	return light, err
+}
+
+// SetLightPIRSensitivityCtx returns a light with the provided ID.
+func (m *MockUnifiCtx) SetLightPIRSensitivityCtx(_ context.Context, lightID string, sensitivity int) (*unifi.Light, error) {
+	light, err := fakeItem[unifi.Light]()
+	if light != nil {
+		light.ID = lightID
+		light.LightDeviceSettings.PirSensitivity = sensitivity
+	}
+
+	return light, err
+}
+
+// PlayChimeCtx does nothing.
+func (m *MockUnifiCtx) PlayChimeCtx(_ context.Context, _ string, _ *unifi.ChimeOptions) error {
+	return nil
+}
+
+// LockDoorlockCtx does nothing.
+func (m *MockUnifiCtx) LockDoorlockCtx(_ context.Context, _ string) error {
+	return nil
+}
+
+// UnlockDoorlockCtx does nothing.
+func (m *MockUnifiCtx) UnlockDoorlockCtx(_ context.Context, _ string) error {
+	return nil
+}
+
+// EnableRTSPCtx returns a camera with the provided ID.
+func (m *MockUnifiCtx) EnableRTSPCtx(ctx context.Context, cameraID string, _ int) (*unifi.Camera, error) {
+	return m.GetCameraByIDCtx(ctx, cameraID)
+}
+
+// DisableRTSPCtx returns a camera with the provided ID.
+func (m *MockUnifiCtx) DisableRTSPCtx(ctx context.Context, cameraID string, _ int) (*unifi.Camera, error) {
+	return m.GetCameraByIDCtx(ctx, cameraID)
+}
+
+// StreamURLsCtx returns the stream URLs of the camera's channels, on a fake NVR.
+func (m *MockUnifiCtx) StreamURLsCtx(_ context.Context, camera *unifi.Camera) ([]*unifi.StreamURL, error) {
+	return (&unifi.NVR{Host: gofakeit.IPv4Address()}).StreamURLs(camera, ""), nil
+}
+
+// GetNVRCtx returns the Protect NVR.
+func (m *MockUnifiCtx) GetNVRCtx(_ context.Context) (*unifi.NVR, error) {
+	return fakeItem[unifi.NVR]()
+}
+
+// GetRecordingCoverageCtx returns a coverage report with no cameras.
+func (m *MockUnifiCtx) GetRecordingCoverageCtx(_ context.Context, start, end time.Time) (*unifi.RecordingCoverage, error) {
+	return &unifi.RecordingCoverage{Start: start, End: end, Cameras: []*unifi.CameraCoverage{}}, nil
+}
+
+// ExportClipsCtx returns a manifest of the cameras, without writing any files.
+func (m *MockUnifiCtx) ExportClipsCtx(
+	_ context.Context, cameraIDs []string, start, end time.Time, _ string, _ *unifi.ExportOptions,
+) (*unifi.ExportManifest, error) {
+	manifest := &unifi.ExportManifest{Start: start, End: end, Created: time.Now()}
+
+	for _, id := range cameraIDs {
+		manifest.Clips = append(manifest.Clips, &unifi.ExportedClip{CameraID: id})
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,113 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SiteError is returned, inside SiteErrors, when polling one site fails.
+type SiteError struct {
+	Site *Site
+	Err  error
+}
+
+// Error satisfies the error interface.
+func (e *SiteError) Error() string {
+	if e.Site == nil {
+		return "site <nil>: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("site %s: %v", pick(e.Site.SiteName, e.Site.Name), e.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (e *SiteError) Unwrap() error {
+	return e.Err
+}
+
+// SiteErrors is returned by methods that poll a list of sites when one or more
+// sites fail. The results from the sites that did not fail are still returned
+// along with this error. Use errors.As to inspect the failed sites, or errors.Is
+// to check for a specific error on any site.
+type SiteErrors []*SiteError
+
+// Error satisfies the error interface.
+func (e SiteErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d site(s) failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns every site error, for errors.Is and errors.As.
+func (e SiteErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// eachSite runs fetch for every site using up to Config.Concurrency workers.
+// Results are returned in the same order as the sites, no matter which
+// finishes first. Failed sites are collected into a SiteErrors.
+func eachSite[T any](ctx context.Context, u *Unifi, sites []*Site,
+	fetch func(context.Context, *Site) ([]T, error),
+) ([]T, error) {
+	var (
+		results = make([][]T, len(sites))
+		errs    = make([]error, len(sites))
+		indexes = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	workers := u.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > len(sites) {
+		workers = len(sites)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i], errs[i] = fetch(withSite(ctx, sites[i]), sites[i])
+			}
+		}()
+	}
+
+	for i := range sites {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	data := make([]T, 0)
+	failed := SiteErrors{}
+
+	for i := range sites {
+		data = append(data, results[i]...)
+
+		if errs[i] != nil {
+			failed = append(failed, &SiteError{Site: sites[i], Err: errs[i]})
+		}
+	}
+
+	if len(failed) > 0 {
+		return data, failed
+	}
+
+	return data, nil
+}
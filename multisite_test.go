@@ -0,0 +1,52 @@
+package unifi // nolint: testpackage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEachSite(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	delays := map[string]time.Duration{"a": 30 * time.Millisecond, "b": 0, "c": 10 * time.Millisecond}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site := strings.Split(r.URL.Path, "/")[3]
+		time.Sleep(delays[site])
+
+		if site == "b" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"data":[{"mac":"%s1"},{"mac":"%s2"}]}`, site, site)
+	}))
+	defer srv.Close()
+
+	u := newUnifi(&Config{URL: srv.URL, Concurrency: 3}, nil)
+	sites := []*Site{{Name: "a", SiteName: "A (a)"}, {Name: "b", SiteName: "B (b)"}, {Name: "c", SiteName: "C (c)"}}
+
+	clients, err := u.GetClients(sites)
+	a.ErrorIs(err, ErrInvalidStatusCode, "site errors must unwrap to the request error")
+
+	var siteErrs SiteErrors
+
+	a.True(errors.As(err, &siteErrs), "a SiteErrors must be returned")
+	a.Len(siteErrs, 1)
+	a.Equal(sites[1], siteErrs[0].Site)
+
+	macs := []string{}
+	for _, c := range clients {
+		macs = append(macs, c.Mac)
+	}
+
+	a.Equal([]string{"a1", "a2", "c1", "c2"}, macs, "partial results must be returned in site order")
+}
@@ -1,32 +1,40 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 // GetNetworks returns a response full of network data from the UniFi Controller.
 func (u *Unifi) GetNetworks(sites []*Site) ([]Network, error) {
-	networks := make([]Network, 0)
+	return u.GetNetworksCtx(context.Background(), sites)
+}
 
-	for _, site := range sites {
-		var response struct {
-			Data []json.RawMessage `json:"data"`
-		}
+// GetNetworksCtx is the same as GetNetworks, but uses the provided context.
+func (u *Unifi) GetNetworksCtx(ctx context.Context, sites []*Site) ([]Network, error) {
+	return eachSite(ctx, u, sites, u.getNetworksSite)
+}
 
-		networkPath := fmt.Sprintf(APINetworkPath, site.Name)
-		if err := u.GetData(networkPath, &response); err != nil {
-			return nil, err
-		}
+func (u *Unifi) getNetworksSite(ctx context.Context, site *Site) ([]Network, error) {
+	var response struct {
+		Data []json.RawMessage `json:"data"`
+	}
+
+	networkPath := fmt.Sprintf(APINetworkPath, site.Name)
+	if err := u.GetDataCtx(ctx, networkPath, &response); err != nil {
+		return nil, err
+	}
 
-		for _, data := range response.Data {
-			network, err := u.parseNetwork(data, site.SiteName)
-			if err != nil {
-				return networks, err
-			}
+	networks := make([]Network, 0, len(response.Data))
 
-			networks = append(networks, *network)
+	for _, data := range response.Data {
+		network, err := u.parseNetwork(data, site.SiteName)
+		if err != nil {
+			return networks, err
 		}
+
+		networks = append(networks, *network)
 	}
 
 	return networks, nil
@@ -36,7 +44,7 @@ func (u *Unifi) GetNetworks(sites []*Site) ([]Network, error) {
 func (u *Unifi) parseNetwork(data json.RawMessage, siteName string) (*Network, error) {
 	network := new(Network)
 	
-	return network, u.unmarshalDevice(siteName, data, network)
+	return network, u.unmarshalDevice("network", data, network, "site", siteName)
 }
 
 // Network is metadata about a network managed by a UniFi controller.
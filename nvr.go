@@ -0,0 +1,395 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrInvalidCoverageWindow is returned by GetRecordingCoverage when start is not before end.
+var ErrInvalidCoverageWindow = fmt.Errorf("recording coverage start must be before end")
+
+// NVRStorageStats is how the NVR's recording space is used.
+type NVRStorageStats struct {
+	Utilization       FlexInt `json:"utilization"` // percent.
+	Capacity          FlexInt `json:"capacity"`
+	RemainingCapacity FlexInt `json:"remainingCapacity"`
+	RecordingSpace    struct {
+		Total     FlexInt `json:"total"`
+		Used      FlexInt `json:"used"`
+		Available FlexInt `json:"available"`
+	} `json:"recordingSpace"`
+	StorageDistribution struct {
+		RecordingTypeDistributions []struct {
+			RecordingType string  `json:"recordingType"` // rotating, timelapse, detections.
+			Size          FlexInt `json:"size"`
+			Percentage    FlexInt `json:"percentage"`
+		} `json:"recordingTypeDistributions"`
+		ResolutionDistributions []struct {
+			Resolution string  `json:"resolution"` // HD, 4K, free.
+			Size       FlexInt `json:"size"`
+			Percentage FlexInt `json:"percentage"`
+		} `json:"resolutionDistributions"`
+	} `json:"storageDistribution"`
+}
+
+// NVRSystemInfo is the health of the NVR hardware.
+type NVRSystemInfo struct {
+	CPU struct {
+		AverageLoad FlexInt `json:"averageLoad"`
+		Temperature FlexInt `json:"temperature"`
+	} `json:"cpu"`
+	Memory struct {
+		Available FlexInt `json:"available"`
+		Free      FlexInt `json:"free"`
+		Total     FlexInt `json:"total"`
+	} `json:"memory"`
+	Storage struct {
+		Available   FlexInt `json:"available"`
+		IsRecycling bool    `json:"isRecycling"` // old recordings are being deleted to make room.
+		Size        FlexInt `json:"size"`
+		Type        string  `json:"type"` // raid, hdd.
+		Used        FlexInt `json:"used"`
+		Devices     []struct {
+			Model   string  `json:"model"`
+			Size    FlexInt `json:"size"`
+			Healthy string  `json:"healthy"`
+		} `json:"devices"`
+	} `json:"storage"`
+	Tmpfs struct {
+		Available FlexInt `json:"available"`
+		Total     FlexInt `json:"total"`
+		Used      FlexInt `json:"used"`
+		Path      string  `json:"path"`
+	} `json:"tmpfs"`
+	UStorage struct {
+		Disks []*NVRDisk  `json:"disks"`
+		Space []*NVRSpace `json:"space"`
+	} `json:"ustorage"`
+}
+
+// NVRDisk is a drive bay in the NVR.
+type NVRDisk struct {
+	Slot         int      `json:"slot"`
+	State        string   `json:"state"` // normal, nodisk, initializing, failed, etc.
+	Type         string   `json:"type"`  // HDD, SSD.
+	Model        string   `json:"model"`
+	Serial       string   `json:"serial"`
+	Firmware     string   `json:"firmware"`
+	RPM          FlexInt  `json:"rpm"`
+	Size         FlexInt  `json:"size"`
+	Healthy      string   `json:"healthy"` // good, warning, bad.
+	Reason       []string `json:"reason"`
+	Temperature  FlexInt  `json:"temperature"`
+	PowerOnHours FlexInt  `json:"poweronhrs"`
+	LifeSpan     FlexInt  `json:"life_span"`
+	BadSectors   FlexInt  `json:"bad_sector"`
+	Threshold    FlexInt  `json:"threshold"`
+	Action       string   `json:"action"`
+	Progress     FlexInt  `json:"progress"`
+	Estimate     FlexInt  `json:"estimate"`
+}
+
+// NVRSpace is a volume on the NVR, usually a RAID array of its disks.
+type NVRSpace struct {
+	Device     string  `json:"device"`
+	SpaceType  string  `json:"space_type"` // raid0, raid1, raid5, etc.
+	TotalBytes FlexInt `json:"total_bytes"`
+	UsedBytes  FlexInt `json:"used_bytes"`
+	Health     string  `json:"health"` // health, degraded, resyncing, etc.
+	Action     string  `json:"action"`
+	Progress   FlexInt `json:"progress"`
+	Estimate   FlexInt `json:"estimate"`
+}
+
+// IsHealthy returns true if the disk has no reported problems. Empty bays are healthy.
+func (d *NVRDisk) IsHealthy() bool {
+	return d.State == "nodisk" || (d.Healthy == "good" && d.State == "normal")
+}
+
+// IsHealthy returns true if the volume is not degraded or rebuilding.
+func (s *NVRSpace) IsHealthy() bool {
+	return s.Health == "health" || s.Health == "healthy"
+}
+
+// Retention returns how long the NVR keeps recordings. Zero means until the disk is full.
+func (n *NVR) Retention() time.Duration {
+	return time.Duration(n.RecordingRetentionDurationMs.Val) * time.Millisecond
+}
+
+// StorageUtilization returns the fraction of the recording space in use, from 0 to 1.
+func (n *NVR) StorageUtilization() float64 {
+	switch {
+	case n.StorageStats != nil && n.StorageStats.RecordingSpace.Total.Val > 0:
+		return n.StorageStats.RecordingSpace.Used.Val / n.StorageStats.RecordingSpace.Total.Val
+	case n.SystemInfo != nil && n.SystemInfo.Storage.Size.Val > 0:
+		return n.SystemInfo.Storage.Used.Val / n.SystemInfo.Storage.Size.Val
+	case n.StorageStats != nil:
+		return n.StorageStats.Utilization.Val / 100 //nolint:gomnd
+	default:
+		return 0
+	}
+}
+
+// UnhealthyDisks returns the disks and volumes that need attention.
+// Both lists are empty on a healthy NVR, or when SystemInfo was not returned.
+func (n *NVR) UnhealthyDisks() ([]*NVRDisk, []*NVRSpace) {
+	disks, spaces := []*NVRDisk{}, []*NVRSpace{}
+
+	if n.SystemInfo == nil {
+		return disks, spaces
+	}
+
+	for _, disk := range n.SystemInfo.UStorage.Disks {
+		if !disk.IsHealthy() {
+			disks = append(disks, disk)
+		}
+	}
+
+	for _, space := range n.SystemInfo.UStorage.Space {
+		if !space.IsHealthy() {
+			spaces = append(spaces, space)
+		}
+	}
+
+	return disks, spaces
+}
+
+// GetNVR returns the Protect NVR, with its storage and hardware health.
+func (u *Unifi) GetNVR() (*NVR, error) {
+	return u.GetNVRCtx(context.Background())
+}
+
+// GetNVRCtx is the same as GetNVR, but uses the provided context.
+func (u *Unifi) GetNVRCtx(ctx context.Context) (*NVR, error) {
+	var nvr NVR
+
+	if err := u.GetDataCtx(ctx, APIProtectNVRPath, &nvr); err != nil {
+		return nil, err
+	}
+
+	return &nvr, nil
+}
+
+// GapReason is why a camera has no recordings for part of a coverage report.
+type GapReason string
+
+// These are the reasons for recording gaps.
+const (
+	GapNoRecordings GapReason = "no recordings"
+	GapBeforeOldest GapReason = "before the oldest recording"
+	GapAfterNewest  GapReason = "after the newest recording"
+	GapDisconnected GapReason = "camera disconnected"
+	// GapRecordingOff is used instead of GapAfterNewest, or GapNoRecordings, for a
+	// camera with recording turned off.
+	GapRecordingOff GapReason = "recording is off"
+)
+
+const (
+	// coverageTolerance is the shortest gap reported; the newest recording time lags a little.
+	coverageTolerance = time.Minute
+	// coverageLookback is how long before the report starts to look for disconnects
+	// that were still going on when it started.
+	coverageLookback = 24 * time.Hour
+)
+
+// RecordingGap is a time when a camera has no recordings.
+type RecordingGap struct {
+	Start  time.Time
+	End    time.Time
+	Reason GapReason
+}
+
+// CameraCoverage is one camera's part of a RecordingCoverage report.
+type CameraCoverage struct {
+	Camera          *Camera
+	OldestRecording time.Time
+	NewestRecording time.Time
+	// Recorded is how much of the report window is not in a gap.
+	Recorded time.Duration
+	// Coverage is Recorded as a fraction of the report window, from 0 to 1.
+	Coverage float64
+	Gaps     []*RecordingGap
+	// StorageBytes is the space the camera's recordings use, and StorageShare is that as
+	// a fraction of the space used by all cameras.
+	StorageBytes int64
+	StorageShare float64
+	// RetentionShortfall is how much younger the oldest recording is than the NVR's
+	// retention policy. It's zero if the camera has recordings as old as the policy.
+	RetentionShortfall time.Duration
+}
+
+// Flagged returns true if the camera has gaps, or does not keep recordings as long as the NVR's retention policy.
+func (c *CameraCoverage) Flagged() bool {
+	return len(c.Gaps) > 0 || c.RetentionShortfall > 0
+}
+
+// RecordingCoverage is a report of the recordings each camera has for a time window.
+type RecordingCoverage struct {
+	NVR       *NVR
+	Start     time.Time
+	End       time.Time
+	Retention time.Duration
+	Cameras   []*CameraCoverage
+}
+
+// Flagged returns the cameras with gaps, or that do not keep recordings as long as the retention policy.
+func (r *RecordingCoverage) Flagged() []*CameraCoverage {
+	flagged := []*CameraCoverage{}
+
+	for _, camera := range r.Cameras {
+		if camera.Flagged() {
+			flagged = append(flagged, camera)
+		}
+	}
+
+	return flagged
+}
+
+// GetRecordingCoverage reports the recordings each camera has between start and end.
+// Gaps are found from each camera's oldest and newest recording, its disconnect events,
+// and its recording mode. Cameras recording only on detections have gaps between
+// detections that are not reported. The end is limited to now, and the start must be
+// before it, or ErrInvalidCoverageWindow is returned.
+func (u *Unifi) GetRecordingCoverage(start, end time.Time) (*RecordingCoverage, error) {
+	return u.GetRecordingCoverageCtx(context.Background(), start, end)
+}
+
+// GetRecordingCoverageCtx is the same as GetRecordingCoverage, but uses the provided context.
+func (u *Unifi) GetRecordingCoverageCtx(ctx context.Context, start, end time.Time) (*RecordingCoverage, error) {
+	now := time.Now()
+	if end.After(now) {
+		end = now
+	}
+
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: %v to %v", ErrInvalidCoverageWindow, start, end)
+	}
+
+	bootstrap, err := u.GetProtectBootstrapCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	disconnects, err := u.GetProtectEventsCtx(ctx, start.Add(-coverageLookback), end,
+		&ProtectEventFilter{Types: []ProtectEventType{ProtectEventDisconnect}})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RecordingCoverage{NVR: bootstrap.NVR, Start: start, End: end}
+	if bootstrap.NVR != nil {
+		report.Retention = bootstrap.NVR.Retention()
+	}
+
+	total := 0.0
+	for _, camera := range bootstrap.Cameras {
+		total += camera.Stats.Storage.Used.Val
+	}
+
+	for _, camera := range bootstrap.Cameras {
+		coverage := report.camera(camera, disconnects, now)
+		if total > 0 {
+			coverage.StorageShare = camera.Stats.Storage.Used.Val / total
+		}
+
+		report.Cameras = append(report.Cameras, coverage)
+	}
+
+	return report, nil
+}
+
+// camera builds the coverage for one camera.
+func (r *RecordingCoverage) camera(camera *Camera, disconnects []*ProtectEvent, now time.Time) *CameraCoverage {
+	coverage := &CameraCoverage{
+		Camera:          camera,
+		StorageBytes:    int64(camera.Stats.Storage.Used.Val),
+		OldestRecording: camera.Stats.Video.RecordingStart.Val,
+		NewestRecording: camera.Stats.Video.RecordingEnd.Val,
+	}
+
+	// Recordings made before recording was turned off are still covered.
+	gaps, missing, after := []*RecordingGap{}, GapNoRecordings, GapAfterNewest
+	if camera.RecordingSettings.Mode == string(RecordingModeNever) {
+		missing, after = GapRecordingOff, GapRecordingOff
+	}
+
+	if coverage.OldestRecording.IsZero() || coverage.NewestRecording.IsZero() {
+		gaps = append(gaps, &RecordingGap{Start: r.Start, End: r.End, Reason: missing})
+	} else {
+		gaps = append(gaps,
+			&RecordingGap{Start: r.Start, End: coverage.OldestRecording, Reason: GapBeforeOldest},
+			&RecordingGap{Start: coverage.NewestRecording, End: r.End, Reason: after})
+	}
+
+	for _, event := range disconnects {
+		if event.Camera != camera.ID {
+			continue
+		}
+
+		end := event.End.Val
+		if end.IsZero() {
+			end = r.End
+		}
+
+		gaps = append(gaps, &RecordingGap{Start: event.Start.Val, End: end, Reason: GapDisconnected})
+	}
+
+	coverage.Gaps = mergeGaps(gaps, r.Start, r.End)
+	coverage.Recorded = r.End.Sub(r.Start)
+
+	for _, gap := range coverage.Gaps {
+		coverage.Recorded -= gap.End.Sub(gap.Start)
+	}
+
+	if window := r.End.Sub(r.Start); window > 0 {
+		coverage.Coverage = float64(coverage.Recorded) / float64(window)
+	}
+
+	if r.Retention > 0 && !coverage.OldestRecording.IsZero() {
+		coverage.RetentionShortfall = max(0, r.Retention-now.Sub(coverage.OldestRecording))
+	}
+
+	return coverage
+}
+
+// mergeGaps limits gaps to the window, drops the ones shorter than coverageTolerance,
+// and joins the ones that overlap. A joined gap keeps the reason of the earlier gap.
+func mergeGaps(gaps []*RecordingGap, start, end time.Time) []*RecordingGap {
+	slices.SortFunc(gaps, func(a, b *RecordingGap) int { return a.Start.Compare(b.Start) })
+
+	merged := []*RecordingGap{}
+
+	for _, gap := range gaps {
+		gap = &RecordingGap{Start: latest(gap.Start, start), End: earliest(gap.End, end), Reason: gap.Reason}
+		if gap.End.Sub(gap.Start) < coverageTolerance {
+			continue
+		}
+
+		if last := len(merged) - 1; last >= 0 && !gap.Start.After(merged[last].End) {
+			merged[last].End = latest(merged[last].End, gap.End)
+			continue
+		}
+
+		merged = append(merged, gap)
+	}
+
+	return merged
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
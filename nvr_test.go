@@ -0,0 +1,157 @@
+package unifi // nolint: testpackage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed examples/nvr.json
+var nvrSample []byte
+
+func TestGetNVR(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == APIPrefixNew+APIProtectNVRPath {
+			_, _ = w.Write(nvrSample)
+		}
+	})
+
+	nvr, err := u.GetNVR()
+	require.NoError(t, err)
+	a.Equal("Home NVR", nvr.Name)
+	a.Equal(30*24*time.Hour, nvr.Retention())
+	a.InDelta(0.824, nvr.StorageUtilization(), 0.001)
+	a.True(nvr.SystemInfo.Storage.IsRecycling)
+	a.Len(nvr.StorageStats.StorageDistribution.ResolutionDistributions, 3)
+
+	disks, spaces := nvr.UnhealthyDisks()
+	require.Len(t, disks, 1, "empty bays must not be reported.")
+	a.Equal(3, disks[0].Slot)
+	a.EqualValues(412, disks[0].BadSectors.Val)
+	require.Len(t, spaces, 1)
+	a.Equal("raid5", spaces[0].SpaceType)
+
+	a.Zero((&NVR{}).StorageUtilization())
+	disks, spaces = (&NVR{}).UnhealthyDisks()
+	a.Empty(disks)
+	a.Empty(spaces)
+}
+
+func TestGetRecordingCoverage(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	now := time.Now()
+	start, end := now.Add(-6*time.Hour), now.Add(time.Hour) // the end is limited to now.
+	ms := func(ago time.Duration) int64 { return now.Add(-ago).UnixMilli() }
+
+	var bootstrap map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(bootstrapSample, &bootstrap))
+
+	camera := func(id, mode string, oldest, newest time.Duration, used float64) map[string]interface{} {
+		var sample map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(cameraSample, &sample))
+		mergeJSON(sample, map[string]interface{}{
+			"id":                id,
+			"name":              id,
+			"recordingSettings": map[string]interface{}{"mode": mode},
+			"stats": map[string]interface{}{
+				"video":   map[string]interface{}{"recordingStart": ms(oldest), "recordingEnd": ms(newest)},
+				"storage": map[string]interface{}{"used": used},
+			},
+		})
+
+		return sample
+	}
+
+	bootstrap["cameras"] = []interface{}{
+		camera("full", "always", 40*24*time.Hour, 0, 600),
+		camera("dropped", "always", 40*24*time.Hour, 0, 200),
+		camera("new", "always", 3*time.Hour, 0, 100),
+		camera("stale", "always", 40*24*time.Hour, 2*time.Hour, 100),
+		camera("off", "never", 40*24*time.Hour, 2*time.Hour, 0), // turned off two hours ago.
+	}
+
+	events := []string{
+		// ended before the window; not a gap.
+		fmt.Sprintf(`{"id":"d1","type":"disconnect","camera":"full","start":%d,"end":%d}`, ms(8*time.Hour), ms(7*time.Hour)),
+		// started before the window, and overlaps a second disconnect.
+		fmt.Sprintf(`{"id":"d2","type":"disconnect","camera":"dropped","start":%d,"end":%d}`, ms(7*time.Hour), ms(5*time.Hour)),
+		fmt.Sprintf(`{"id":"d3","type":"disconnect","camera":"dropped","start":%d,"end":%d}`, ms(5*time.Hour+time.Minute), ms(4*time.Hour)),
+		// too short to report.
+		fmt.Sprintf(`{"id":"d4","type":"disconnect","camera":"full","start":%d,"end":%d}`, ms(time.Hour), ms(time.Hour-time.Second)),
+	}
+
+	queries := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefixNew + APIProtectBootstrapPath:
+			_ = json.NewEncoder(w).Encode(bootstrap)
+		case APIPrefixNew + APIProtectEventsPath:
+			queries <- r.URL.RawQuery
+			_, _ = fmt.Fprintf(w, "[%s,%s,%s,%s]", events[0], events[1], events[2], events[3])
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := NewUnifi(&Config{URL: srv.URL, APIKey: "key"})
+	require.NoError(t, err)
+
+	report, err := u.GetRecordingCoverage(start, end)
+	require.NoError(t, err)
+	a.Contains(<-queries, "types=disconnect")
+	a.Equal(30*24*time.Hour, report.Retention)
+	a.False(report.End.After(time.Now()), "the end must be limited to now.")
+	require.Len(t, report.Cameras, 5)
+
+	byID := map[string]*CameraCoverage{}
+	for _, coverage := range report.Cameras {
+		byID[coverage.Camera.ID] = coverage
+	}
+
+	full := byID["full"]
+	a.Empty(full.Gaps)
+	a.InDelta(1, full.Coverage, 0.001)
+	a.Zero(full.RetentionShortfall)
+	a.InDelta(0.6, full.StorageShare, 0.001)
+	a.False(full.Flagged())
+
+	dropped := byID["dropped"]
+	require.Len(t, dropped.Gaps, 1, "overlapping disconnects must be merged.")
+	a.Equal(GapDisconnected, dropped.Gaps[0].Reason)
+	a.True(dropped.Gaps[0].Start.Equal(report.Start), "gaps must be limited to the window.")
+	a.Equal(2*time.Hour, dropped.Gaps[0].End.Sub(dropped.Gaps[0].Start).Round(time.Second))
+	a.InDelta(4.0/6, dropped.Coverage, 0.01)
+
+	recent := byID["new"]
+	require.Len(t, recent.Gaps, 1)
+	a.Equal(GapBeforeOldest, recent.Gaps[0].Reason)
+	a.InDelta((30*24*time.Hour - 3*time.Hour).Hours(), recent.RetentionShortfall.Hours(), 0.01)
+
+	stale := byID["stale"]
+	require.Len(t, stale.Gaps, 1)
+	a.Equal(GapAfterNewest, stale.Gaps[0].Reason)
+
+	off := byID["off"]
+	require.Len(t, off.Gaps, 1)
+	a.Equal(GapRecordingOff, off.Gaps[0].Reason)
+	a.True(off.Gaps[0].Start.Equal(off.NewestRecording), "recordings from before it was turned off must count.")
+	a.Equal(4*time.Hour, off.Recorded.Round(time.Second))
+
+	a.Len(report.Flagged(), 4)
+
+	_, err = u.GetRecordingCoverage(end, start)
+	a.ErrorIs(err, ErrInvalidCoverageWindow)
+}
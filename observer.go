@@ -0,0 +1,123 @@
+package unifi
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// RequestObserver is called after every request sent to the controller,
+// including retries and replays. Provide one in Config.Observer to collect
+// request metrics. See the promunifi package for a Prometheus collector.
+type RequestObserver interface {
+	ObserveRequest(ctx context.Context, info *RequestInfo)
+}
+
+// RequestObserverFunc is an adapter to allow the use of ordinary functions as a RequestObserver.
+type RequestObserverFunc func(ctx context.Context, info *RequestInfo)
+
+// ObserveRequest calls f(ctx, info).
+func (f RequestObserverFunc) ObserveRequest(ctx context.Context, info *RequestInfo) {
+	f(ctx, info)
+}
+
+// RequestInfo describes a completed request. It is passed to a RequestObserver.
+type RequestInfo struct {
+	Method string
+	// Path is the API path template, like APIDevicePath, not the expanded URL.
+	// This keeps site names and IDs out of metric labels.
+	Path string
+	// Status is the HTTP status code; 0 if no response was received.
+	Status   int
+	Bytes    int
+	Duration time.Duration
+	// Err is the error returned for this request, if any.
+	Err error
+}
+
+// apiPathTemplates are matched against request paths to find the template used to create them.
+var apiPathTemplates = []string{
+	APIEventPath,
+	APIClientPath,
+	APIAllUserPath,
+	APINetworkPath,
+	APIDevicePath,
+	APIEventPathIDS,
+	APIEventPathAlarms,
+	APIAnomaliesPath,
+	APICommandPath,
+	APIDevMgrPath,
+	APISiteDPI,
+	APIClientDPI,
+	APIRogueAP,
+	APIProtectCameraPath,
+	APIProtectCameraSnapshotPath,
+	APIProtectPTZMovePath,
+	APIProtectPTZPositionPath,
+	APIProtectPTZPresetsPath,
+	APIProtectPTZPresetPath,
+	APIProtectPTZGotoPath,
+	APIProtectPTZPatrolsPath,
+	APIProtectPTZPatrolStartPath,
+	APIProtectPTZPatrolStopPath,
+	APIProtectLightPath,
+	APIProtectChimePlayPath,
+	APIProtectDoorlockOpenPath,
+	APIProtectDoorlockClosePath,
+	APIProtectEventThumbnailPath,
+	APIProtectEventHeatmapPath,
+}
+
+// observe sends a completed request to the Observer, if one was provided.
+func (u *Unifi) observe(ctx context.Context, start time.Time, method, path string, status, size int, err error) {
+	if u.Observer == nil {
+		return
+	}
+
+	u.Observer.ObserveRequest(ctx, &RequestInfo{
+		Method:   method,
+		Path:     pathTemplate(path),
+		Status:   status,
+		Bytes:    size,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+}
+
+// pathTemplate returns the API path template that matches an expanded request path.
+// The new-style API prefix and the query string are removed. Unknown site paths have
+// their site name replaced with %s; other unknown paths are returned as-is.
+func pathTemplate(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	path = strings.TrimPrefix(path, APIPrefixNew)
+
+	for _, tmpl := range apiPathTemplates {
+		if matchTemplate(tmpl, path) {
+			return tmpl
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(path, "/api/s/"); ok {
+		if _, after, ok := strings.Cut(rest, "/"); ok {
+			return "/api/s/%s/" + after
+		}
+	}
+
+	return path
+}
+
+// matchTemplate returns true if each path segment is equal to the template's, or the template's is %s or %d.
+func matchTemplate(tmpl, path string) bool {
+	tmplParts, pathParts := strings.Split(tmpl, "/"), strings.Split(path, "/")
+	if len(tmplParts) != len(pathParts) {
+		return false
+	}
+
+	for i := range tmplParts {
+		if tmplParts[i] != "%s" && tmplParts[i] != "%d" && tmplParts[i] != pathParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
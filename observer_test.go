@@ -0,0 +1,20 @@
+package unifi // nolint: testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTemplate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(APIDevicePath, pathTemplate("/api/s/default/stat/device"))
+	a.Equal(APIDevicePath, pathTemplate(APIPrefixNew+"/api/s/default/stat/device"))
+	a.Equal(APIDevMgrPath, pathTemplate("/api/s/abc123/cmd/devmgr"))
+	a.Equal(APISiteList, pathTemplate(APISiteList))
+	a.Equal("/api/s/%s/stat/health", pathTemplate("/api/s/default/stat/health"), "site names must be hidden")
+	a.Equal("/api/video/export", pathTemplate("/api/video/export?camera=abc"), "queries must be removed")
+	a.Equal(APIProtectCameraSnapshotPath, pathTemplate(APIPrefixNew+"/api/cameras/6183e5f40271f603e7000440/snapshot?w=640"))
+}
@@ -0,0 +1,445 @@
+package promunifi
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/secure-passage/unifi"
+)
+
+// Client is the part of unifi.UnifiClient used by the Collector.
+// A *unifi.Unifi satisfies this interface.
+type Client interface {
+	GetSites() ([]*unifi.Site, error)
+	GetDevices(sites []*unifi.Site) (*unifi.Devices, error)
+	GetClients(sites []*unifi.Site) ([]*unifi.Client, error)
+	GetSiteDPI(sites []*unifi.Site) ([]*unifi.DPITable, error)
+}
+
+// Client labels that may be provided in Opts.ClientLabels.
+const (
+	ClientLabelName  = "name"
+	ClientLabelMAC   = "mac"
+	ClientLabelESSID = "essid"
+	ClientLabelRadio = "radio"
+	ClientLabelOUI   = "oui"
+)
+
+// ErrInvalidClientLabel is returned by NewCollector for an unknown or repeated client label.
+var ErrInvalidClientLabel = fmt.Errorf("invalid client label")
+
+// Opts controls which metrics a Collector exports, and with which labels.
+// The zero value exports everything except per-application DPI data.
+type Opts struct {
+	// Namespace prefixes every metric name. Default: unifi.
+	Namespace string
+	// ClientLabels are added to client metrics, after the site label. Each must be
+	// one of the ClientLabel constants, and may only be listed once. Default: name and mac. Clients that share the same label values with
+	// another client in the same site are only exported once.
+	ClientLabels []string
+	// DPIApplications exports DPI data per application, instead of per category.
+	DPIApplications bool
+	// NoClients, NoPorts, NoRadios, NoOutlets and NoDPI turn off groups of metrics.
+	NoClients bool
+	NoPorts   bool
+	NoRadios  bool
+	NoOutlets bool
+	NoDPI     bool
+}
+
+// Collector is a prometheus.Collector that polls a controller on every scrape.
+// Site, device, port, radio, PDU outlet, temperature, client and DPI data are
+// turned into gauges and counters. Partial data is exported when some sites fail.
+type Collector struct {
+	client Client
+	opts   Opts
+	descs  map[string]*prometheus.Desc
+}
+
+// Make sure Collector and unifi.Unifi satisfy the interfaces.
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+	_ Client               = (unifi.UnifiClient)(nil)
+)
+
+// NewCollector returns a Collector for a controller. opts may be nil.
+// ErrInvalidClientLabel is returned if opts has an invalid client label.
+func NewCollector(client Client, opts *Opts) (*Collector, error) {
+	c := &Collector{client: client, descs: make(map[string]*prometheus.Desc)}
+
+	if opts != nil {
+		c.opts = *opts
+	}
+
+	if c.opts.Namespace == "" {
+		c.opts.Namespace = "unifi"
+	}
+
+	if c.opts.ClientLabels == nil {
+		c.opts.ClientLabels = []string{ClientLabelName, ClientLabelMAC}
+	}
+
+	if err := checkClientLabels(c.opts.ClientLabels); err != nil {
+		return nil, err
+	}
+
+	dpiLabels := []string{"site", "category"}
+	if c.opts.DPIApplications {
+		dpiLabels = append(dpiLabels, "application")
+	}
+
+	deviceLabels := []string{"site", "name", "mac", "model", "type"}
+	portLabels := []string{"site", "device", "device_mac", "port", "port_name"}
+	radioLabels := []string{"site", "device", "device_mac", "radio", "radio_name"}
+	outletLabels := []string{"site", "device", "device_mac", "outlet", "outlet_name"}
+	clientLabels := append([]string{"site"}, c.opts.ClientLabels...)
+
+	for name, def := range map[string]struct {
+		help   string
+		labels []string
+	}{
+		"collector_success":               {"Whether the last poll of this part of the controller succeeded.", []string{"collector"}},
+		"collector_duration_seconds":      {"How long the last poll of this part of the controller took.", []string{"collector"}},
+		"site_alarms":                     {"New alarms on the site.", []string{"site"}},
+		"site_users":                      {"Users connected to a site subsystem.", []string{"site", "subsystem"}},
+		"site_guests":                     {"Guests connected to a site subsystem.", []string{"site", "subsystem"}},
+		"site_receive_bytes_per_second":   {"Bytes per second received by a site subsystem.", []string{"site", "subsystem"}},
+		"site_transmit_bytes_per_second":  {"Bytes per second transmitted by a site subsystem.", []string{"site", "subsystem"}},
+		"device_uptime_seconds":           {"Device uptime.", deviceLabels},
+		"device_cpu_utilization_ratio":    {"Device CPU utilization, 0 to 1.", deviceLabels},
+		"device_memory_utilization_ratio": {"Device memory utilization, 0 to 1.", deviceLabels},
+		"device_temperature_celsius":      {"Device temperature sensors.", append(deviceLabels, "sensor")},
+		"port_receive_bytes_total":        {"Bytes received on a device port.", portLabels},
+		"port_transmit_bytes_total":       {"Bytes transmitted on a device port.", portLabels},
+		"port_up":                         {"Whether a device port is up.", portLabels},
+		"port_poe_power_watts":            {"Power provided by a device port.", portLabels},
+		"radio_channel_utilization_ratio": {"Channel utilization of an access point radio, 0 to 1.", radioLabels},
+		"radio_stations":                  {"Stations connected to an access point radio.", radioLabels},
+		"radio_transmit_power_dbm":        {"Transmit power of an access point radio.", radioLabels},
+		"pdu_outlet_power_watts":          {"Power used by a PDU outlet.", outletLabels},
+		"pdu_outlet_current_amperes":      {"Current used by a PDU outlet.", outletLabels},
+		"pdu_outlet_voltage_volts":        {"Voltage provided by a PDU outlet.", outletLabels},
+		"client_rssi":                     {"Client received signal strength indicator.", clientLabels},
+		"client_signal_dbm":               {"Client signal strength.", clientLabels},
+		"client_satisfaction_ratio":       {"Client satisfaction, 0 to 1.", clientLabels},
+		"client_uptime_seconds":           {"How long the client has been connected.", clientLabels},
+		"client_receive_bytes_total":      {"Bytes received from the client.", clientLabels},
+		"client_transmit_bytes_total":     {"Bytes transmitted to the client.", clientLabels},
+		"site_dpi_receive_bytes_total":    {"Bytes received by the site, by deep packet inspection type.", dpiLabels},
+		"site_dpi_transmit_bytes_total":   {"Bytes transmitted by the site, by deep packet inspection type.", dpiLabels},
+	} {
+		c.descs[name] = prometheus.NewDesc(c.opts.Namespace+"_"+name, def.help, def.labels, nil)
+	}
+
+	return c, nil
+}
+
+// checkClientLabels returns an error for a label that is unknown or listed twice.
+func checkClientLabels(labels []string) error {
+	seen := make(map[string]bool, len(labels))
+
+	for _, label := range labels {
+		switch label {
+		case ClientLabelName, ClientLabelMAC, ClientLabelESSID, ClientLabelRadio, ClientLabelOUI:
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidClientLabel, label)
+		}
+
+		if seen[label] {
+			return fmt.Errorf("%w: %q is repeated", ErrInvalidClientLabel, label)
+		}
+
+		seen[label] = true
+	}
+
+	return nil
+}
+
+// Describe satisfies the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+// Collect satisfies the prometheus.Collector interface. It polls the controller.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	var sites []*unifi.Site
+
+	c.poll(ch, "sites", func() (err error) {
+		sites, err = c.client.GetSites()
+		c.collectSites(ch, sites)
+
+		return err
+	})
+
+	if len(sites) == 0 {
+		return
+	}
+
+	c.poll(ch, "devices", func() error {
+		devices, err := c.client.GetDevices(sites)
+		c.collectDevices(ch, devices)
+
+		return err //nolint:wrapcheck
+	})
+
+	if !c.opts.NoClients {
+		c.poll(ch, "clients", func() error {
+			clients, err := c.client.GetClients(sites)
+			c.collectClients(ch, clients)
+
+			return err //nolint:wrapcheck
+		})
+	}
+
+	if !c.opts.NoDPI {
+		c.poll(ch, "dpi", func() error {
+			dpi, err := c.client.GetSiteDPI(sites)
+			c.collectDPI(ch, dpi)
+
+			return err //nolint:wrapcheck
+		})
+	}
+}
+
+// poll runs a collection and exports its duration and whether it succeeded.
+func (c *Collector) poll(ch chan<- prometheus.Metric, name string, collect func() error) {
+	start := time.Now()
+	success := 1.0
+
+	if err := collect(); err != nil {
+		success = 0
+	}
+
+	c.gauge(ch, "collector_success", success, name)
+	c.gauge(ch, "collector_duration_seconds", time.Since(start).Seconds(), name)
+}
+
+func (c *Collector) gauge(ch chan<- prometheus.Metric, name string, value float64, labels ...string) {
+	c.metric(ch, name, prometheus.GaugeValue, value, labels)
+}
+
+func (c *Collector) counter(ch chan<- prometheus.Metric, name string, value float64, labels ...string) {
+	c.metric(ch, name, prometheus.CounterValue, value, labels)
+}
+
+// metric sends a metric, or an invalid metric that fails the scrape with the reason.
+func (c *Collector) metric(
+	ch chan<- prometheus.Metric, name string, typ prometheus.ValueType, value float64, labels []string,
+) {
+	metric, err := prometheus.NewConstMetric(c.descs[name], typ, value, labels...)
+	if err != nil {
+		metric = prometheus.NewInvalidMetric(c.descs[name], err)
+	}
+
+	ch <- metric
+}
+
+func (c *Collector) collectSites(ch chan<- prometheus.Metric, sites []*unifi.Site) {
+	for _, site := range sites {
+		c.gauge(ch, "site_alarms", site.NumNewAlarms.Val, site.SiteName)
+
+		for _, health := range site.Health {
+			c.gauge(ch, "site_users", health.NumUser.Val, site.SiteName, health.Subsystem)
+			c.gauge(ch, "site_guests", health.NumGuest.Val, site.SiteName, health.Subsystem)
+			c.gauge(ch, "site_receive_bytes_per_second", health.RxBytesR.Val, site.SiteName, health.Subsystem)
+			c.gauge(ch, "site_transmit_bytes_per_second", health.TxBytesR.Val, site.SiteName, health.Subsystem)
+		}
+	}
+}
+
+// device is the data shared by the device types that the Collector exports.
+type device struct {
+	labels  []string // site, name, mac, model, type
+	uptime  unifi.FlexInt
+	stats   unifi.SystemStats
+	temps   []unifi.Temperature
+	ports   []unifi.Port
+	radios  unifi.RadioTableStats
+	outlets []unifi.OutletTable
+}
+
+func (c *Collector) collectDevices(ch chan<- prometheus.Metric, devices *unifi.Devices) {
+	if devices == nil {
+		return
+	}
+
+	for _, d := range devices.UAPs {
+		c.collectDevice(ch, &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, ports: d.PortTable, radios: d.RadioTableStats,
+		})
+	}
+
+	for _, d := range devices.USWs {
+		c.collectDevice(ch, &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, ports: d.PortTable,
+		})
+	}
+
+	for _, d := range devices.USGs {
+		dev := &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, temps: d.Temperatures,
+		}
+
+		for _, port := range d.PortTable {
+			if port != nil {
+				dev.ports = append(dev.ports, *port)
+			}
+		}
+
+		c.collectDevice(ch, dev)
+	}
+
+	for _, d := range devices.UDMs {
+		dev := &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, temps: d.Temperatures, ports: d.PortTable,
+		}
+
+		if d.RadioTableStats != nil {
+			dev.radios = *d.RadioTableStats
+		}
+
+		c.collectDevice(ch, dev)
+	}
+
+	for _, d := range devices.UXGs {
+		c.collectDevice(ch, &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, temps: d.Temperatures, ports: d.PortTable,
+		})
+	}
+
+	for _, d := range devices.PDUs {
+		c.collectDevice(ch, &device{
+			labels: []string{d.SiteName, d.Name, d.Mac, d.Model, d.Type},
+			uptime: d.Uptime, stats: d.SystemStats, ports: d.PortTable, outlets: d.OutletTable,
+		})
+	}
+}
+
+func (c *Collector) collectDevice(ch chan<- prometheus.Metric, dev *device) {
+	c.gauge(ch, "device_uptime_seconds", dev.uptime.Val, dev.labels...)
+	c.gauge(ch, "device_cpu_utilization_ratio", dev.stats.CPU.Val/100, dev.labels...)    //nolint:gomnd
+	c.gauge(ch, "device_memory_utilization_ratio", dev.stats.Mem.Val/100, dev.labels...) //nolint:gomnd
+
+	seen := make(map[string]bool)
+
+	for sensor, temp := range dev.stats.Temps {
+		if temp != nil && !seen[sensor] {
+			seen[sensor] = true
+			c.gauge(ch, "device_temperature_celsius", temp.Val, append(dev.labels, sensor)...)
+		}
+	}
+
+	for _, temp := range dev.temps {
+		if !seen[temp.Name] {
+			seen[temp.Name] = true
+			c.gauge(ch, "device_temperature_celsius", temp.Value, append(dev.labels, temp.Name)...)
+		}
+	}
+
+	site, name, mac := dev.labels[0], dev.labels[1], dev.labels[2]
+
+	if !c.opts.NoPorts {
+		for _, port := range dev.ports {
+			labels := []string{site, name, mac, strconv.FormatFloat(port.PortIdx.Val, 'f', -1, 64), port.Name}
+			c.counter(ch, "port_receive_bytes_total", port.RxBytes.Val, labels...)
+			c.counter(ch, "port_transmit_bytes_total", port.TxBytes.Val, labels...)
+			c.gauge(ch, "port_up", boolValue(port.Up.Val), labels...)
+			c.gauge(ch, "port_poe_power_watts", port.PoePower.Val, labels...)
+		}
+	}
+
+	if !c.opts.NoRadios {
+		for _, radio := range dev.radios {
+			labels := []string{site, name, mac, radio.Radio, radio.Name}
+			c.gauge(ch, "radio_channel_utilization_ratio", radio.CuTotal.Val/100, labels...) //nolint:gomnd
+			c.gauge(ch, "radio_stations", radio.NumSta.Val, labels...)
+			c.gauge(ch, "radio_transmit_power_dbm", radio.TxPower.Val, labels...)
+		}
+	}
+
+	if !c.opts.NoOutlets {
+		for _, outlet := range dev.outlets {
+			labels := []string{site, name, mac, strconv.FormatFloat(outlet.Index.Val, 'f', -1, 64), outlet.Name}
+			c.gauge(ch, "pdu_outlet_power_watts", outlet.OutletPower.Val, labels...)
+			c.gauge(ch, "pdu_outlet_current_amperes", outlet.OutletCurrent.Val, labels...)
+			c.gauge(ch, "pdu_outlet_voltage_volts", outlet.OutletVoltage.Val, labels...)
+		}
+	}
+}
+
+func (c *Collector) collectClients(ch chan<- prometheus.Metric, clients []*unifi.Client) {
+	seen := make(map[string]bool)
+
+	for _, client := range clients {
+		labels := []string{client.SiteName}
+
+		for _, label := range c.opts.ClientLabels {
+			switch label {
+			case ClientLabelName:
+				labels = append(labels, client.Name)
+			case ClientLabelMAC:
+				labels = append(labels, client.Mac)
+			case ClientLabelESSID:
+				labels = append(labels, client.Essid)
+			case ClientLabelRadio:
+				labels = append(labels, client.Radio)
+			case ClientLabelOUI:
+				labels = append(labels, client.Oui)
+			}
+		}
+
+		key := strconv.Quote(labels[0])
+		for _, label := range labels[1:] {
+			key += "," + strconv.Quote(label)
+		}
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		c.gauge(ch, "client_rssi", client.Rssi.Val, labels...)
+		c.gauge(ch, "client_signal_dbm", client.Signal.Val, labels...)
+		c.gauge(ch, "client_satisfaction_ratio", client.Satisfaction.Val/100, labels...) //nolint:gomnd
+		c.gauge(ch, "client_uptime_seconds", client.Uptime.Val, labels...)
+		c.counter(ch, "client_receive_bytes_total", client.RxBytes.Val+client.WiredRxBytes.Val, labels...)
+		c.counter(ch, "client_transmit_bytes_total", client.TxBytes.Val+client.WiredTxBytes.Val, labels...)
+	}
+}
+
+func (c *Collector) collectDPI(ch chan<- prometheus.Metric, tables []*unifi.DPITable) {
+	for _, table := range tables {
+		data := table.ByCat
+		if c.opts.DPIApplications {
+			data = table.ByApp
+		}
+
+		for _, dpi := range data {
+			labels := []string{table.SiteName, unifi.DPICats.Get(int(dpi.Cat.Val))}
+			if c.opts.DPIApplications {
+				labels = append(labels, unifi.DPIApps.GetApp(int(dpi.Cat.Val), int(dpi.App.Val)))
+			}
+
+			c.counter(ch, "site_dpi_receive_bytes_total", dpi.RxBytes.Val, labels...)
+			c.counter(ch, "site_dpi_transmit_bytes_total", dpi.TxBytes.Val, labels...)
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,101 @@
+package promunifi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/secure-passage/unifi"
+	"github.com/secure-passage/unifi/promunifi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSiteFailed = errors.New("site failed")
+
+type fakeClient struct{}
+
+func (fakeClient) GetSites() ([]*unifi.Site, error) {
+	return []*unifi.Site{{Name: "default", SiteName: "Default", NumNewAlarms: unifi.FlexInt{Val: 2}}}, nil
+}
+
+func (fakeClient) GetDevices(_ []*unifi.Site) (*unifi.Devices, error) {
+	return &unifi.Devices{
+		UAPs: []*unifi.UAP{{
+			SiteName: "Default", Name: "ap1", Mac: "aa", Model: "U7PG2", Type: "uap",
+			PortTable:       []unifi.Port{{PortIdx: unifi.FlexInt{Val: 1}, Name: "Port 1", RxBytes: unifi.FlexInt{Val: 100}}},
+			RadioTableStats: unifi.RadioTableStats{{Name: "wifi0", Radio: "ng", CuTotal: unifi.FlexInt{Val: 25}}},
+			SystemStats:     unifi.SystemStats{Temps: unifi.TempStatusByName{"CPU": &unifi.FlexTemp{Val: 51}}},
+		}},
+		PDUs: []*unifi.PDU{{
+			SiteName: "Default", Name: "pdu1", Mac: "bb", Model: "USPPDUP", Type: "pdu",
+			OutletTable: []unifi.OutletTable{{Index: unifi.FlexInt{Val: 3}, Name: "NAS", OutletPower: unifi.FlexInt{Val: 42.5}}},
+		}},
+	}, nil
+}
+
+func (fakeClient) GetClients(_ []*unifi.Site) ([]*unifi.Client, error) {
+	return []*unifi.Client{
+		{SiteName: "Default", Name: "phone", Mac: "cc", Essid: "home", Satisfaction: unifi.FlexInt{Val: 90}},
+		{SiteName: "Default", Name: "laptop", Mac: "dd", Essid: "home", Satisfaction: unifi.FlexInt{Val: 50}},
+	}, errSiteFailed
+}
+
+func (fakeClient) GetSiteDPI(_ []*unifi.Site) ([]*unifi.DPITable, error) {
+	return nil, nil
+}
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	collector, err := promunifi.NewCollector(fakeClient{}, nil)
+	require.NoError(t, err)
+
+	expected := `
+# HELP unifi_collector_success Whether the last poll of this part of the controller succeeded.
+# TYPE unifi_collector_success gauge
+unifi_collector_success{collector="clients"} 0
+unifi_collector_success{collector="devices"} 1
+unifi_collector_success{collector="dpi"} 1
+unifi_collector_success{collector="sites"} 1
+# HELP unifi_site_alarms New alarms on the site.
+# TYPE unifi_site_alarms gauge
+unifi_site_alarms{site="Default"} 2
+# HELP unifi_port_receive_bytes_total Bytes received on a device port.
+# TYPE unifi_port_receive_bytes_total counter
+unifi_port_receive_bytes_total{device="ap1",device_mac="aa",port="1",port_name="Port 1",site="Default"} 100
+# HELP unifi_radio_channel_utilization_ratio Channel utilization of an access point radio, 0 to 1.
+# TYPE unifi_radio_channel_utilization_ratio gauge
+unifi_radio_channel_utilization_ratio{device="ap1",device_mac="aa",radio="ng",radio_name="wifi0",site="Default"} 0.25
+# HELP unifi_pdu_outlet_power_watts Power used by a PDU outlet.
+# TYPE unifi_pdu_outlet_power_watts gauge
+unifi_pdu_outlet_power_watts{device="pdu1",device_mac="bb",outlet="3",outlet_name="NAS",site="Default"} 42.5
+# HELP unifi_device_temperature_celsius Device temperature sensors.
+# TYPE unifi_device_temperature_celsius gauge
+unifi_device_temperature_celsius{mac="aa",model="U7PG2",name="ap1",sensor="CPU",site="Default",type="uap"} 51
+# HELP unifi_client_satisfaction_ratio Client satisfaction, 0 to 1.
+# TYPE unifi_client_satisfaction_ratio gauge
+unifi_client_satisfaction_ratio{mac="cc",name="phone",site="Default"} 0.9
+unifi_client_satisfaction_ratio{mac="dd",name="laptop",site="Default"} 0.5
+`
+	a.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"unifi_collector_success", "unifi_site_alarms", "unifi_port_receive_bytes_total",
+		"unifi_radio_channel_utilization_ratio", "unifi_pdu_outlet_power_watts",
+		"unifi_device_temperature_celsius", "unifi_client_satisfaction_ratio"))
+
+	// Clients that share label values are exported once.
+	collector, err = promunifi.NewCollector(fakeClient{}, &promunifi.Opts{
+		ClientLabels: []string{promunifi.ClientLabelESSID}, NoPorts: true,
+	})
+	require.NoError(t, err)
+	a.Equal(1, testutil.CollectAndCount(collector, "unifi_client_satisfaction_ratio"))
+	a.Equal(0, testutil.CollectAndCount(collector, "unifi_port_receive_bytes_total"))
+
+	_, err = promunifi.NewCollector(fakeClient{}, &promunifi.Opts{ClientLabels: []string{"hostname"}})
+	a.ErrorIs(err, promunifi.ErrInvalidClientLabel)
+
+	_, err = promunifi.NewCollector(fakeClient{}, &promunifi.Opts{ClientLabels: []string{"mac", "mac"}})
+	a.ErrorIs(err, promunifi.ErrInvalidClientLabel, "repeated labels must be rejected")
+}
@@ -0,0 +1,86 @@
+// Package promunifi provides Prometheus collectors for the unifi library.
+package promunifi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/secure-passage/unifi"
+)
+
+// RequestCollector is a unifi.RequestObserver and a prometheus.Collector.
+// Provide it in unifi.Config.Observer and register it with a prometheus.Registerer
+// to export the latency, size and status of every request sent to the controller.
+type RequestCollector struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	bytes    *prometheus.CounterVec
+}
+
+// Make sure RequestCollector satisfies both interfaces.
+var (
+	_ unifi.RequestObserver = (*RequestCollector)(nil)
+	_ prometheus.Collector  = (*RequestCollector)(nil)
+)
+
+// NewRequestCollector returns a RequestCollector with metric names prefixed by namespace.
+// The constLabels are added to every metric; use them to tell controllers apart.
+func NewRequestCollector(namespace string, constLabels prometheus.Labels) *RequestCollector {
+	return &RequestCollector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "controller",
+			Name:        "requests_total",
+			Help:        "Requests sent to the controller, by API path, method and status code.",
+			ConstLabels: constLabels,
+		}, []string{"path", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "controller",
+			Name:        "request_duration_seconds",
+			Help:        "Time to receive and read controller responses, by API path and method.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"path", "method"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "controller",
+			Name:        "response_bytes_total",
+			Help:        "Bytes read from controller responses, by API path and method.",
+			ConstLabels: constLabels,
+		}, []string{"path", "method"}),
+	}
+}
+
+// ObserveRequest satisfies the unifi.RequestObserver interface.
+func (c *RequestCollector) ObserveRequest(_ context.Context, info *unifi.RequestInfo) {
+	status := "error" // no response.
+	if info.Status != 0 {
+		status = strconv.Itoa(info.Status)
+	}
+
+	method := info.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	c.requests.WithLabelValues(info.Path, method, status).Inc()
+	c.duration.WithLabelValues(info.Path, method).Observe(info.Duration.Seconds())
+	c.bytes.WithLabelValues(info.Path, method).Add(float64(info.Bytes))
+}
+
+// Describe satisfies the prometheus.Collector interface.
+func (c *RequestCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.bytes.Describe(ch)
+}
+
+// Collect satisfies the prometheus.Collector interface.
+func (c *RequestCollector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.bytes.Collect(ch)
+}
@@ -0,0 +1,46 @@
+package promunifi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/secure-passage/unifi"
+	"github.com/secure-passage/unifi/promunifi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCollector(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/s/broken/") {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	collector := promunifi.NewRequestCollector("unifi", nil)
+	u, err := unifi.NewUnifi(&unifi.Config{URL: srv.URL, APIKey: "key", Observer: collector})
+	a.NoError(err)
+
+	_, err = u.GetDevices([]*unifi.Site{{Name: "default"}, {Name: "other"}, {Name: "broken"}})
+	a.Error(err)
+
+	expected := `
+# HELP unifi_controller_requests_total Requests sent to the controller, by API path, method and status code.
+# TYPE unifi_controller_requests_total counter
+unifi_controller_requests_total{method="GET",path="/api/s/%s/stat/device",status="200"} 2
+unifi_controller_requests_total{method="GET",path="/api/s/%s/stat/device",status="502"} 1
+`
+	a.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected), "unifi_controller_requests_total"))
+	a.Equal(2, testutil.CollectAndCount(collector, "unifi_controller_request_duration_seconds",
+		"unifi_controller_response_bytes_total"), "one series per path and method")
+}
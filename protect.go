@@ -0,0 +1,254 @@
+package unifi
+
+import (
+	"context"
+)
+
+// Bootstrap is the complete state of a UniFi Protect NVR. Every Protect
+// integration starts here; the LastUpdateID is used to subscribe to updates.
+type Bootstrap struct {
+	AuthUserID   string          `json:"authUserId"`
+	AccessKey    string          `json:"accessKey"`
+	NVR          *NVR            `json:"nvr"`
+	Cameras      []*Camera       `json:"cameras"`
+	Lights       []*Light        `json:"lights"`
+	Sensors      []*Sensor       `json:"sensors"`
+	Chimes       []*Chime        `json:"chimes"`
+	Viewers      []*Viewer       `json:"viewers"`
+	Doorlocks    []*Doorlock     `json:"doorlocks"`
+	Bridges      []*Bridge       `json:"bridges"`
+	Liveviews    []*Liveview     `json:"liveviews"`
+	Users        []*ProtectUser  `json:"users"`
+	Groups       []*ProtectGroup `json:"groups"`
+	LastUpdateID string          `json:"lastUpdateId"`
+}
+
+// ProtectDevice contains the fields shared by every Protect device type.
+// Uptime is a duration in milliseconds, not a timestamp.
+type ProtectDevice struct {
+	ID                    string   `json:"id"`
+	Mac                   string   `json:"mac"`
+	Host                  string   `json:"host"`
+	ConnectionHost        string   `json:"connectionHost"`
+	Type                  string   `json:"type"`
+	ModelKey              string   `json:"modelKey"`
+	Name                  string   `json:"name"`
+	MarketName            string   `json:"marketName"`
+	NvrMac                string   `json:"nvrMac"`
+	State                 string   `json:"state"`
+	UpSince               FlexTime `json:"upSince"`
+	Uptime                int64    `json:"uptime"`
+	LastSeen              FlexTime `json:"lastSeen"`
+	ConnectedSince        FlexTime `json:"connectedSince"`
+	FirmwareVersion       string   `json:"firmwareVersion"`
+	LatestFirmwareVersion string   `json:"latestFirmwareVersion"`
+	FirmwareBuild         string   `json:"firmwareBuild"`
+	IsConnected           bool     `json:"isConnected"`
+	IsAdopted             bool     `json:"isAdopted"`
+	IsAdopting            bool     `json:"isAdopting"`
+	IsAdoptedByOther      bool     `json:"isAdoptedByOther"`
+	IsUpdating            bool     `json:"isUpdating"`
+	IsRebooting           bool     `json:"isRebooting"`
+	CanAdopt              bool     `json:"canAdopt"`
+}
+
+// NVR is the Network Video Recorder running UniFi Protect.
+type NVR struct {
+	ID                           string   `json:"id"`
+	Mac                          string   `json:"mac"`
+	Host                         string   `json:"host"`
+	Hosts                        []string `json:"hosts"`
+	Name                         string   `json:"name"`
+	Type                         string   `json:"type"`
+	ModelKey                     string   `json:"modelKey"`
+	MarketName                   string   `json:"marketName"`
+	Version                      string   `json:"version"`
+	FirmwareVersion              string   `json:"firmwareVersion"`
+	HardwarePlatform             string   `json:"hardwarePlatform"`
+	HardwareID                   string   `json:"hardwareId"`
+	Timezone                     string   `json:"timezone"`
+	UpSince                      FlexTime `json:"upSince"`
+	Uptime                       int64    `json:"uptime"`
+	LastSeen                     FlexTime `json:"lastSeen"`
+	LastUpdateAt                 FlexTime `json:"lastUpdateAt"`
+	IsHardware                   bool     `json:"isHardware"`
+	IsConnectedToCloud           bool     `json:"isConnectedToCloud"`
+	IsUpdating                   bool     `json:"isUpdating"`
+	IsRecordingDisabled          bool     `json:"isRecordingDisabled"`
+	IsRecordingMotionOnly        bool     `json:"isRecordingMotionOnly"`
+	EnableAutomaticBackups       bool     `json:"enableAutomaticBackups"`
+	RecordingRetentionDurationMs FlexInt  `json:"recordingRetentionDurationMs"`
+	IsStatsGatheringEnabled      bool     `json:"isStatsGatheringEnabled"`
+	// StorageStats and SystemInfo are returned by GetNVR, and in the bootstrap.
+	StorageStats *NVRStorageStats `json:"storageStats"`
+	SystemInfo   *NVRSystemInfo   `json:"systemInfo"`
+	Ports        struct {
+		HTTP        int `json:"http"`
+		HTTPS       int `json:"https"`
+		Rtsp        int `json:"rtsp"`
+		Rtsps       int `json:"rtsps"`
+		Rtmp        int `json:"rtmp"`
+		UpdatesWs   int `json:"updatesWs"`
+		CameraHTTPS int `json:"cameraHttps"`
+		CameraTCP   int `json:"cameraTcp"`
+		LiveWs      int `json:"liveWs"`
+		LiveWss     int `json:"liveWss"`
+	} `json:"ports"`
+}
+
+// Light is a Protect floodlight.
+type Light struct {
+	ProtectDevice
+	Camera              string   `json:"camera"` // paired camera ID.
+	IsPirMotionDetected bool     `json:"isPirMotionDetected"`
+	IsLightOn           bool     `json:"isLightOn"`
+	IsLocating          bool     `json:"isLocating"`
+	IsDark              bool     `json:"isDark"`
+	LastMotion          FlexTime `json:"lastMotion"`
+	LightOnSettings     struct {
+		IsLedForceOn bool `json:"isLedForceOn"`
+	} `json:"lightOnSettings"`
+	LightModeSettings struct {
+		Mode     string `json:"mode"`
+		EnableAt string `json:"enableAt"`
+	} `json:"lightModeSettings"`
+	LightDeviceSettings struct {
+		IsIndicatorEnabled bool   `json:"isIndicatorEnabled"`
+		LedLevel           int    `json:"ledLevel"`
+		LuxSensitivity     string `json:"luxSensitivity"`
+		PirDuration        int64  `json:"pirDuration"`
+		PirSensitivity     int    `json:"pirSensitivity"`
+	} `json:"lightDeviceSettings"`
+}
+
+// SensorStat is a single environmental reading from a Sensor.
+type SensorStat struct {
+	Value  float64 `json:"value"`
+	Status string  `json:"status"`
+}
+
+// Sensor is a Protect door, window, motion or environmental sensor.
+type Sensor struct {
+	ProtectDevice
+	Camera        string `json:"camera"` // paired camera ID.
+	MountType     string `json:"mountType"`
+	BatteryStatus struct {
+		Percentage int  `json:"percentage"`
+		IsLow      bool `json:"isLow"`
+	} `json:"batteryStatus"`
+	Stats struct {
+		Light       SensorStat `json:"light"`
+		Humidity    SensorStat `json:"humidity"`
+		Temperature SensorStat `json:"temperature"`
+	} `json:"stats"`
+	IsOpened            bool     `json:"isOpened"`
+	OpenStatusChangedAt FlexTime `json:"openStatusChangedAt"`
+	IsMotionDetected    bool     `json:"isMotionDetected"`
+	MotionDetectedAt    FlexTime `json:"motionDetectedAt"`
+	AlarmTriggeredAt    FlexTime `json:"alarmTriggeredAt"`
+	LeakDetectedAt      FlexTime `json:"leakDetectedAt"`
+	TamperingDetectedAt FlexTime `json:"tamperingDetectedAt"`
+	MotionSettings      struct {
+		IsEnabled   bool `json:"isEnabled"`
+		Sensitivity int  `json:"sensitivity"`
+	} `json:"motionSettings"`
+}
+
+// Chime is a Protect doorbell chime.
+type Chime struct {
+	ProtectDevice
+	Volume       int      `json:"volume"`
+	CameraIDs    []string `json:"cameraIds"`
+	LastRing     FlexTime `json:"lastRing"`
+	RingSettings []struct {
+		CameraID    string `json:"cameraId"`
+		RepeatTimes int    `json:"repeatTimes"`
+		TrackType   string `json:"trackType"`
+		Volume      int    `json:"volume"`
+	} `json:"ringSettings"`
+}
+
+// Doorlock is a Protect smart lock.
+type Doorlock struct {
+	ProtectDevice
+	Camera          string `json:"camera"` // paired camera ID.
+	LockStatus      string `json:"lockStatus"`
+	AutoCloseTimeMs int64  `json:"autoCloseTimeMs"`
+	EnableHomekit   bool   `json:"enableHomekit"`
+	BatteryStatus   struct {
+		Percentage int  `json:"percentage"`
+		IsLow      bool `json:"isLow"`
+	} `json:"batteryStatus"`
+}
+
+// Viewer is a Protect Viewport that displays a Liveview on a screen.
+type Viewer struct {
+	ProtectDevice
+	Liveview        string `json:"liveview"`
+	SoftwareVersion string `json:"softwareVersion"`
+}
+
+// Bridge is a Protect bluetooth bridge; sensors and locks connect through these.
+type Bridge struct {
+	ProtectDevice
+	Platform string `json:"platform"`
+}
+
+// Liveview is a layout of cameras shown in the Protect UI or on a Viewer.
+type Liveview struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ModelKey  string `json:"modelKey"`
+	IsDefault bool   `json:"isDefault"`
+	IsGlobal  bool   `json:"isGlobal"`
+	Layout    int    `json:"layout"`
+	Owner     string `json:"owner"`
+	Slots     []struct {
+		Cameras       []string `json:"cameras"`
+		CycleMode     string   `json:"cycleMode"`
+		CycleInterval int      `json:"cycleInterval"`
+	} `json:"slots"`
+}
+
+// ProtectUser is a user account on the Protect NVR.
+type ProtectUser struct {
+	ID            string   `json:"id"`
+	ModelKey      string   `json:"modelKey"`
+	Name          string   `json:"name"`
+	FirstName     string   `json:"firstName"`
+	LastName      string   `json:"lastName"`
+	Email         string   `json:"email"`
+	LocalUsername string   `json:"localUsername"`
+	Groups        []string `json:"groups"`
+	Permissions   []string `json:"permissions"`
+	IsOwner       bool     `json:"isOwner"`
+	Enabled       bool     `json:"enabled"`
+	LastLoginIP   string   `json:"lastLoginIp"`
+	LastLoginTime FlexTime `json:"lastLoginTime"`
+}
+
+// ProtectGroup is a group of users and their permissions on the Protect NVR.
+type ProtectGroup struct {
+	ID          string   `json:"id"`
+	ModelKey    string   `json:"modelKey"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	IsDefault   bool     `json:"isDefault"`
+	Permissions []string `json:"permissions"`
+}
+
+// GetProtectBootstrap returns the complete state of the Protect NVR.
+func (u *Unifi) GetProtectBootstrap() (*Bootstrap, error) {
+	return u.GetProtectBootstrapCtx(context.Background())
+}
+
+// GetProtectBootstrapCtx returns the complete state of the Protect NVR using the provided context.
+func (u *Unifi) GetProtectBootstrapCtx(ctx context.Context) (*Bootstrap, error) {
+	var bootstrap Bootstrap
+
+	if err := u.GetDataCtx(ctx, APIProtectBootstrapPath, &bootstrap); err != nil {
+		return nil, err
+	}
+
+	return &bootstrap, nil
+}
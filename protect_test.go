@@ -0,0 +1,160 @@
+package unifi // nolint: testpackage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed examples/bootstrap.json
+var bootstrapSample []byte
+
+//go:embed examples/protectevents.json
+var protectEventsSample []byte
+
+// newProtectServer returns a UniFi OS console stand-in that serves the bootstrap fixture,
+// and passes every other request to handler, if one is provided.
+func newProtectServer(t *testing.T, handler http.HandlerFunc) (*Unifi, *httptest.Server) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+		case r.URL.Path == APIPrefixNew+APIProtectBootstrapPath:
+			_, _ = w.Write(bootstrapSample)
+		case handler != nil:
+			handler(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := NewUnifi(&Config{URL: srv.URL, APIKey: "key"})
+	require.NoError(t, err)
+
+	return u, srv
+}
+
+func TestGetProtectBootstrap(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	u, _ := newProtectServer(t, nil)
+
+	bootstrap, err := u.GetProtectBootstrap()
+	require.NoError(t, err)
+
+	a.Equal("9f6c4d2b-8a3e-4c1f-b7d5-2e0a9c8b7f61", bootstrap.LastUpdateID)
+	a.Equal("Home NVR", bootstrap.NVR.Name)
+	a.Equal(7441, bootstrap.NVR.Ports.Rtsps)
+	a.EqualValues(2592000000, bootstrap.NVR.RecordingRetentionDurationMs.Val)
+	a.Len(bootstrap.Cameras, 1)
+	a.Equal("Front Door", bootstrap.Cameras[0].Name)
+	a.Equal("Driveway", bootstrap.Lights[0].Name)
+	a.Equal(45, bootstrap.Lights[0].LightDeviceSettings.PirSensitivity)
+	a.InDelta(21.5, bootstrap.Sensors[0].Stats.Temperature.Value, 0.01)
+	a.True(bootstrap.Sensors[0].IsOpened)
+	a.Equal("CLOSED", bootstrap.Doorlocks[0].LockStatus)
+	a.Equal([]string{bootstrap.Cameras[0].ID}, bootstrap.Chimes[0].CameraIDs)
+	a.Equal("Lobby TV", bootstrap.Viewers[0].Name)
+	a.Equal("mt7621", bootstrap.Bridges[0].Platform)
+	a.Equal(bootstrap.Liveviews[0].ID, bootstrap.Viewers[0].Liveview)
+	a.True(bootstrap.Users[0].IsOwner)
+	a.Equal(bootstrap.Groups[0].ID, bootstrap.Users[0].Groups[0])
+}
+
+// serveProtectEvents pages through the events fixture like the NVR does; filters are ignored.
+func serveProtectEvents(t *testing.T, queries chan<- string) http.HandlerFunc {
+	t.Helper()
+
+	var events []json.RawMessage
+
+	require.NoError(t, json.Unmarshal(protectEventsSample, &events))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != APIPrefixNew+APIProtectEventsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		queries <- r.URL.RawQuery
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page := events[min(offset, len(events)):min(offset+limit, len(events))]
+
+		_ = json.NewEncoder(w).Encode(page)
+	}
+}
+
+func TestGetProtectEvents(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	queries := make(chan string, 10)
+	start, end := time.UnixMilli(1700122000000), time.UnixMilli(1700123000000)
+
+	u, _ := newProtectServer(t, serveProtectEvents(t, queries))
+
+	events, err := u.GetProtectEvents(start, end, &ProtectEventFilter{PageSize: 3})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	a.Len(queries, 2, "4 events with a page size of 3 must take two requests.")
+	a.Equal("end=1700123000000&limit=3&offset=0&orderDirection=ASC&start=1700122000000", <-queries)
+	a.Contains(<-queries, "offset=3")
+
+	a.Equal(ProtectEventMotion, events[0].Type)
+	a.Equal(63, events[0].Score)
+	a.Equal(16725*time.Millisecond, events[0].Duration())
+	a.Equal("e-6557a1f2006d0703e7001b41", events[0].Thumbnail)
+	a.Equal([]SmartDetectType{SmartDetectPerson, SmartDetectPackage}, events[1].SmartDetectTypes)
+	a.True(events[1].IsFavorite)
+	a.Zero(events[3].Duration(), "an event in progress has no duration.")
+
+	events, err = u.GetProtectEvents(start, end, &ProtectEventFilter{
+		Cameras:          []string{"6183e5f40271f603e7000440"},
+		Types:            []ProtectEventType{ProtectEventSmartDetectZone},
+		SmartDetectTypes: []SmartDetectType{SmartDetectPackage},
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	a.Equal("6557a20b00e80703e7001b4a", events[0].ID)
+
+	query := <-queries
+	a.Contains(query, "cameras=6183e5f40271f603e7000440")
+	a.Contains(query, "types=smartDetectZone")
+	a.Contains(query, "smartDetectTypes=package")
+
+	events, err = u.GetProtectEvents(start, end, &ProtectEventFilter{PageSize: 1, Limit: 2})
+	require.NoError(t, err)
+	a.Len(events, 2)
+	a.Len(queries, 2, "paging must stop at the limit.")
+}
+
+func TestGetProtectEventsIgnoredOffset(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var events []json.RawMessage
+
+	require.NoError(t, json.Unmarshal(protectEventsSample, &events))
+
+	requests := 0
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		_ = json.NewEncoder(w).Encode(events[:min(limit, len(events))]) // always the first page.
+	})
+
+	page, err := u.GetProtectEvents(time.UnixMilli(1700122000000), time.UnixMilli(1700123000000),
+		&ProtectEventFilter{PageSize: 2})
+	require.NoError(t, err)
+	a.Len(page, 2, "a repeated page must not be returned twice.")
+	a.Equal(2, requests, "paging must stop when a page has no new events.")
+}
@@ -0,0 +1,216 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// These are returned when a Protect device action is rejected, or is not applied by the NVR.
+var (
+	ErrInvalidDeviceAction = fmt.Errorf("invalid protect device action")
+	ErrLightNotUpdated     = fmt.Errorf("light did not apply the update")
+)
+
+// These are the Doorlock LockStatus values.
+const (
+	LockStatusOpen    = "OPEN"
+	LockStatusOpening = "OPENING"
+	LockStatusClosed  = "CLOSED"
+	LockStatusClosing = "CLOSING"
+)
+
+// These are the light brightness limits; Light.LightDeviceSettings.LedLevel.
+const (
+	LightLevelMin = 1
+	LightLevelMax = 6
+)
+
+// ChimeOptions change how a chime plays. Zero values use the chime's settings.
+type ChimeOptions struct {
+	Volume      int `json:"volume,omitempty"`      // 1 to 100.
+	RepeatTimes int `json:"repeatTimes,omitempty"` // 1 to 6.
+}
+
+// GetLights returns all of the Protect floodlights known to the NVR.
+func (u *Unifi) GetLights() ([]*Light, error) {
+	return u.GetLightsCtx(context.Background())
+}
+
+// GetLightsCtx is the same as GetLights, but uses the provided context.
+func (u *Unifi) GetLightsCtx(ctx context.Context) ([]*Light, error) {
+	var lights []*Light
+
+	if err := u.GetDataCtx(ctx, APIProtectLightsPath, &lights); err != nil {
+		return nil, err
+	}
+
+	return lights, nil
+}
+
+// GetSensors returns all of the Protect sensors known to the NVR.
+func (u *Unifi) GetSensors() ([]*Sensor, error) {
+	return u.GetSensorsCtx(context.Background())
+}
+
+// GetSensorsCtx is the same as GetSensors, but uses the provided context.
+func (u *Unifi) GetSensorsCtx(ctx context.Context) ([]*Sensor, error) {
+	var sensors []*Sensor
+
+	if err := u.GetDataCtx(ctx, APIProtectSensorsPath, &sensors); err != nil {
+		return nil, err
+	}
+
+	return sensors, nil
+}
+
+// GetChimes returns all of the Protect chimes known to the NVR.
+func (u *Unifi) GetChimes() ([]*Chime, error) {
+	return u.GetChimesCtx(context.Background())
+}
+
+// GetChimesCtx is the same as GetChimes, but uses the provided context.
+func (u *Unifi) GetChimesCtx(ctx context.Context) ([]*Chime, error) {
+	var chimes []*Chime
+
+	if err := u.GetDataCtx(ctx, APIProtectChimesPath, &chimes); err != nil {
+		return nil, err
+	}
+
+	return chimes, nil
+}
+
+// GetDoorlocks returns all of the Protect smart locks known to the NVR.
+func (u *Unifi) GetDoorlocks() ([]*Doorlock, error) {
+	return u.GetDoorlocksCtx(context.Background())
+}
+
+// GetDoorlocksCtx is the same as GetDoorlocks, but uses the provided context.
+func (u *Unifi) GetDoorlocksCtx(ctx context.Context) ([]*Doorlock, error) {
+	var doorlocks []*Doorlock
+
+	if err := u.GetDataCtx(ctx, APIProtectDoorlocksPath, &doorlocks); err != nil {
+		return nil, err
+	}
+
+	return doorlocks, nil
+}
+
+// SetLight turns a floodlight on, or back off. A light that is off still turns on
+// for motion if its mode is motion; this only forces it on.
+func (u *Unifi) SetLight(lightID string, on bool) (*Light, error) {
+	return u.SetLightCtx(context.Background(), lightID, on)
+}
+
+// SetLightCtx is the same as SetLight, but uses the provided context.
+func (u *Unifi) SetLightCtx(ctx context.Context, lightID string, on bool) (*Light, error) {
+	return u.updateLight(ctx, lightID, map[string]interface{}{
+		"lightOnSettings": map[string]interface{}{"isLedForceOn": on},
+	})
+}
+
+// SetLightBrightness sets a floodlight's brightness, from LightLevelMin to LightLevelMax.
+func (u *Unifi) SetLightBrightness(lightID string, level int) (*Light, error) {
+	return u.SetLightBrightnessCtx(context.Background(), lightID, level)
+}
+
+// SetLightBrightnessCtx is the same as SetLightBrightness, but uses the provided context.
+func (u *Unifi) SetLightBrightnessCtx(ctx context.Context, lightID string, level int) (*Light, error) {
+	if level < LightLevelMin || level > LightLevelMax {
+		return nil, fmt.Errorf("%w: light level must be from %d to %d, not %d",
+			ErrInvalidDeviceAction, LightLevelMin, LightLevelMax, level)
+	}
+
+	return u.updateLight(ctx, lightID, map[string]interface{}{
+		"lightDeviceSettings": map[string]interface{}{"ledLevel": level},
+	})
+}
+
+// SetLightPIRSensitivity sets how much motion turns on a floodlight, from 0 to 100.
+func (u *Unifi) SetLightPIRSensitivity(lightID string, sensitivity int) (*Light, error) {
+	return u.SetLightPIRSensitivityCtx(context.Background(), lightID, sensitivity)
+}
+
+// SetLightPIRSensitivityCtx is the same as SetLightPIRSensitivity, but uses the provided context.
+func (u *Unifi) SetLightPIRSensitivityCtx(ctx context.Context, lightID string, sensitivity int) (*Light, error) {
+	if sensitivity < 0 || sensitivity > 100 {
+		return nil, fmt.Errorf("%w: PIR sensitivity must be from 0 to 100, not %d", ErrInvalidDeviceAction, sensitivity)
+	}
+
+	return u.updateLight(ctx, lightID, map[string]interface{}{
+		"lightDeviceSettings": map[string]interface{}{"pirSensitivity": sensitivity},
+	})
+}
+
+// updateLight sends a partial light update, and checks the returned light has it.
+func (u *Unifi) updateLight(ctx context.Context, lightID string, patch map[string]interface{}) (*Light, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	var light Light
+
+	if err := u.PatchDataCtx(ctx, fmt.Sprintf(APIProtectLightPath, lightID), &light, string(data)); err != nil {
+		return nil, err
+	}
+
+	if fields := unappliedFields(data, &light); len(fields) > 0 {
+		return &light, fmt.Errorf("%w: %s", ErrLightNotUpdated, strings.Join(fields, ", "))
+	}
+
+	return &light, nil
+}
+
+// PlayChime rings a chime. Pass nil options to use the chime's volume and repeat settings.
+func (u *Unifi) PlayChime(chimeID string, opts *ChimeOptions) error {
+	return u.PlayChimeCtx(context.Background(), chimeID, opts)
+}
+
+// PlayChimeCtx is the same as PlayChime, but uses the provided context.
+func (u *Unifi) PlayChimeCtx(ctx context.Context, chimeID string, opts *ChimeOptions) error {
+	if opts == nil {
+		opts = &ChimeOptions{}
+	}
+
+	if opts.Volume < 0 || opts.Volume > 100 {
+		return fmt.Errorf("%w: chime volume must be from 1 to 100, not %d", ErrInvalidDeviceAction, opts.Volume)
+	}
+
+	if opts.RepeatTimes < 0 || opts.RepeatTimes > 6 {
+		return fmt.Errorf("%w: chime repeat must be from 1 to 6, not %d", ErrInvalidDeviceAction, opts.RepeatTimes)
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	_, err = u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectChimePlayPath, chimeID), string(data))
+
+	return err
+}
+
+// LockDoorlock closes a smart lock. The lock reports LockStatusClosing until it's done.
+func (u *Unifi) LockDoorlock(doorlockID string) error {
+	return u.LockDoorlockCtx(context.Background(), doorlockID)
+}
+
+// LockDoorlockCtx is the same as LockDoorlock, but uses the provided context.
+func (u *Unifi) LockDoorlockCtx(ctx context.Context, doorlockID string) error {
+	_, err := u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectDoorlockClosePath, doorlockID))
+	return err
+}
+
+// UnlockDoorlock opens a smart lock. The lock reports LockStatusOpening until it's done,
+// and locks again after its AutoCloseTimeMs, if set.
+func (u *Unifi) UnlockDoorlock(doorlockID string) error {
+	return u.UnlockDoorlockCtx(context.Background(), doorlockID)
+}
+
+// UnlockDoorlockCtx is the same as UnlockDoorlock, but uses the provided context.
+func (u *Unifi) UnlockDoorlockCtx(ctx context.Context, doorlockID string) error {
+	_, err := u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectDoorlockOpenPath, doorlockID))
+	return err
+}
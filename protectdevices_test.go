@@ -0,0 +1,142 @@
+package unifi // nolint: testpackage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	//go:embed examples/lights.json
+	lightsSample []byte
+	//go:embed examples/sensors.json
+	sensorsSample []byte
+	//go:embed examples/chimes.json
+	chimesSample []byte
+	//go:embed examples/doorlocks.json
+	doorlocksSample []byte
+)
+
+func TestGetProtectDevices(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		samples := map[string][]byte{
+			APIPrefixNew + APIProtectLightsPath:    lightsSample,
+			APIPrefixNew + APIProtectSensorsPath:   sensorsSample,
+			APIPrefixNew + APIProtectChimesPath:    chimesSample,
+			APIPrefixNew + APIProtectDoorlocksPath: doorlocksSample,
+		}
+
+		_, _ = w.Write(samples[r.URL.Path])
+	})
+
+	lights, err := u.GetLights()
+	require.NoError(t, err)
+	require.Len(t, lights, 2)
+	a.Equal("Driveway", lights[0].Name)
+	a.Equal("6183e5f40271f603e7000440", lights[0].Camera)
+	a.Equal(6, lights[0].LightDeviceSettings.LedLevel)
+	a.True(lights[1].LightOnSettings.IsLedForceOn)
+	a.Equal("always", lights[1].LightModeSettings.Mode)
+	a.Equal(time.UnixMilli(1699999000000), lights[0].LastMotion.Val)
+	a.Equal(time.UnixMilli(1699999999000), lights[0].LastSeen.Val)
+	a.True(lights[1].LastMotion.Val.IsZero(), "a null timestamp must be the zero time.")
+
+	sensors, err := u.GetSensors()
+	require.NoError(t, err)
+	require.Len(t, sensors, 2)
+	a.Equal("door", sensors[0].MountType)
+	a.True(sensors[0].IsOpened)
+	a.Equal(time.UnixMilli(1699999900000), sensors[0].OpenStatusChangedAt.Val)
+	a.InDelta(21.5, sensors[0].Stats.Temperature.Value, 0.01)
+	a.True(sensors[1].BatteryStatus.IsLow)
+
+	chimes, err := u.GetChimes()
+	require.NoError(t, err)
+	require.Len(t, chimes, 1)
+	a.Equal([]string{"6183e5f40271f603e7000440"}, chimes[0].CameraIDs)
+	a.Equal(1, chimes[0].RingSettings[0].RepeatTimes)
+	a.Equal(time.UnixMilli(1699990000000), chimes[0].LastRing.Val)
+
+	doorlocks, err := u.GetDoorlocks()
+	require.NoError(t, err)
+	require.Len(t, doorlocks, 2)
+	a.Equal(LockStatusClosed, doorlocks[0].LockStatus)
+	a.Equal(LockStatusOpen, doorlocks[1].LockStatus)
+	a.EqualValues(15000, doorlocks[0].AutoCloseTimeMs)
+}
+
+func TestProtectDeviceActions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	requests := make(chan string, 1)
+	ignore := false // the NVR ignores light updates when true.
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r.Method + " " + r.URL.Path + " " + string(body)
+
+		if r.URL.Path != APIPrefixNew+"/api/lights/6183e5f4003c6103e7000460" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+
+		var (
+			lights  []map[string]interface{}
+			changes map[string]interface{}
+		)
+
+		_ = json.Unmarshal(lightsSample, &lights)
+		_ = json.Unmarshal(body, &changes)
+
+		if !ignore {
+			mergeJSON(lights[0], changes)
+		}
+
+		_ = json.NewEncoder(w).Encode(lights[0])
+	})
+
+	light, err := u.SetLight("6183e5f4003c6103e7000460", true)
+	require.NoError(t, err)
+	a.Equal(`PATCH /proxy/protect/api/lights/6183e5f4003c6103e7000460 {"lightOnSettings":{"isLedForceOn":true}}`,
+		<-requests)
+	a.True(light.LightOnSettings.IsLedForceOn)
+
+	light, err = u.SetLightBrightness("6183e5f4003c6103e7000460", 2)
+	require.NoError(t, err)
+	<-requests
+	a.Equal(2, light.LightDeviceSettings.LedLevel)
+	a.Equal(45, light.LightDeviceSettings.PirSensitivity, "other settings must not change.")
+
+	ignore = true
+	_, err = u.SetLightPIRSensitivity("6183e5f4003c6103e7000460", 90)
+	<-requests
+	a.ErrorIs(err, ErrLightNotUpdated)
+	a.ErrorContains(err, "lightDeviceSettings.pirSensitivity")
+
+	_, err = u.SetLightBrightness("6183e5f4003c6103e7000460", 7)
+	a.ErrorIs(err, ErrInvalidDeviceAction)
+	_, err = u.SetLightPIRSensitivity("6183e5f4003c6103e7000460", -1)
+	a.ErrorIs(err, ErrInvalidDeviceAction)
+
+	require.NoError(t, u.PlayChime("6183e5f4003c6103e70004a0", nil))
+	a.Equal("POST /proxy/protect/api/chimes/6183e5f4003c6103e70004a0/play-speaker {}", <-requests)
+	require.NoError(t, u.PlayChime("6183e5f4003c6103e70004a0", &ChimeOptions{Volume: 50, RepeatTimes: 2}))
+	a.Equal(`POST /proxy/protect/api/chimes/6183e5f4003c6103e70004a0/play-speaker {"volume":50,"repeatTimes":2}`,
+		<-requests)
+	a.ErrorIs(u.PlayChime("6183e5f4003c6103e70004a0", &ChimeOptions{RepeatTimes: 7}), ErrInvalidDeviceAction)
+
+	require.NoError(t, u.LockDoorlock("6183e5f4003c6103e7000490"))
+	a.Equal("POST /proxy/protect/api/doorlocks/6183e5f4003c6103e7000490/close ", <-requests)
+	require.NoError(t, u.UnlockDoorlock("6183e5f4003c6103e7000490"))
+	a.Equal("POST /proxy/protect/api/doorlocks/6183e5f4003c6103e7000490/open ", <-requests)
+}
@@ -0,0 +1,292 @@
+package unifi
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// ErrInvalidProtectPacket is returned when a Protect update packet cannot be decoded.
+var ErrInvalidProtectPacket = fmt.Errorf("invalid protect update packet")
+
+// ProtectUpdateAction is what happened to the object in a ProtectUpdate.
+type ProtectUpdateAction string
+
+// These are the Protect update actions.
+const (
+	ProtectUpdateAdd    ProtectUpdateAction = "add"
+	ProtectUpdateUpdate ProtectUpdateAction = "update"
+	ProtectUpdateRemove ProtectUpdateAction = "remove"
+)
+
+// Protect update packets contain an action frame followed by a payload frame.
+// Every frame starts with an 8 byte header: the frame type, the payload format
+// (1 JSON, 2 string, 3 buffer), a deflated flag, an unused byte, then the payload
+// size as a big endian uint32.
+const (
+	protectHeaderSize   = 8
+	protectFrameAction  = 1
+	protectFramePayload = 2
+	protectFormatJSON   = 1
+	// protectMaxFrameSize limits how large a deflated frame may inflate to.
+	protectMaxFrameSize = 16 << 20
+)
+
+// ProtectUpdate is a change pushed by the Protect NVR. Use SubscribeProtect to get these.
+type ProtectUpdate struct {
+	Action   ProtectUpdateAction `json:"action"`
+	ModelKey string              `json:"modelKey"` // camera, event, light, sensor, nvr, etc.
+	ID       string              `json:"id"`
+	UpdateID string              `json:"newUpdateId"`
+	// Data is the payload: the whole object for an add, and the changed fields for an update.
+	// JSON payloads are JSON; string and buffer payloads are returned as is.
+	Data []byte `json:"-"`
+	// Camera is set on camera messages. Updates are applied to the last known state of
+	// the camera, so this is always the whole camera. On remove, it's the removed camera.
+	Camera *Camera `json:"-"`
+	// Event is set on event messages, with updates applied like Camera.
+	Event *ProtectEvent `json:"-"`
+	// isJSON is true if Data is JSON.
+	isJSON bool
+}
+
+// protectFrame is one decoded frame from an update packet.
+type protectFrame struct {
+	typ    byte
+	format byte
+	data   []byte
+}
+
+// decodeProtectPacket decodes the action and payload frames of an update packet.
+// The payload is not parsed, and may be nil if the packet has no payload frame.
+func decodeProtectPacket(packet []byte) (*ProtectUpdate, error) {
+	action, packet, err := readProtectFrame(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	if action.typ != protectFrameAction || action.format != protectFormatJSON {
+		return nil, fmt.Errorf("%w: first frame is type %d, format %d", ErrInvalidProtectPacket, action.typ, action.format)
+	}
+
+	update := &ProtectUpdate{}
+	if err := json.Unmarshal(action.data, update); err != nil {
+		return nil, fmt.Errorf("%w: decoding action: %v", ErrInvalidProtectPacket, err) //nolint:errorlint
+	}
+
+	if len(packet) == 0 {
+		return update, nil
+	}
+
+	payload, _, err := readProtectFrame(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.typ != protectFramePayload {
+		return nil, fmt.Errorf("%w: second frame is type %d", ErrInvalidProtectPacket, payload.typ)
+	}
+
+	update.Data = payload.data
+	update.isJSON = payload.format == protectFormatJSON
+
+	return update, nil
+}
+
+// readProtectFrame decodes the first frame in a packet, and returns the rest of the packet.
+func readProtectFrame(packet []byte) (protectFrame, []byte, error) {
+	if len(packet) < protectHeaderSize {
+		return protectFrame{}, nil, fmt.Errorf("%w: short header, %d bytes", ErrInvalidProtectPacket, len(packet))
+	}
+
+	size := binary.BigEndian.Uint32(packet[4:protectHeaderSize])
+	if uint64(len(packet)-protectHeaderSize) < uint64(size) {
+		return protectFrame{}, nil, fmt.Errorf("%w: short payload, %d of %d bytes",
+			ErrInvalidProtectPacket, len(packet)-protectHeaderSize, size)
+	}
+
+	end := protectHeaderSize + int(size)
+	frame := protectFrame{typ: packet[0], format: packet[1], data: packet[protectHeaderSize:end]}
+
+	if packet[2] == 1 {
+		inflater, err := zlib.NewReader(bytes.NewReader(frame.data))
+		if err != nil {
+			return protectFrame{}, nil, fmt.Errorf("%w: inflating frame: %v", ErrInvalidProtectPacket, err) //nolint:errorlint
+		}
+		defer inflater.Close()
+
+		if frame.data, err = io.ReadAll(io.LimitReader(inflater, protectMaxFrameSize+1)); err != nil {
+			return protectFrame{}, nil, fmt.Errorf("%w: inflating frame: %v", ErrInvalidProtectPacket, err) //nolint:errorlint
+		}
+
+		if len(frame.data) > protectMaxFrameSize {
+			return protectFrame{}, nil, fmt.Errorf("%w: frame inflates to more than %d bytes",
+				ErrInvalidProtectPacket, protectMaxFrameSize)
+		}
+	}
+
+	return frame, packet[end:], nil
+}
+
+// protectState is the last known state of the cameras and in-progress events.
+// Updates only contain the changed fields, so they are merged into this state.
+// The state is kept as decoded JSON, and a new object is created for every update.
+type protectState struct {
+	LastUpdateID string                            `json:"lastUpdateId"`
+	Cameras      []map[string]interface{}          `json:"cameras"`
+	objects      map[string]map[string]interface{} // keyed by model key and id.
+}
+
+func (s *protectState) init() {
+	s.objects = make(map[string]map[string]interface{}, len(s.Cameras))
+
+	for _, camera := range s.Cameras {
+		id, _ := camera["id"].(string)
+		s.objects["camera/"+id] = camera
+	}
+
+	s.Cameras = nil
+}
+
+// apply sets Camera or Event on an update, and saves the changes.
+func (s *protectState) apply(update *ProtectUpdate) error {
+	s.LastUpdateID = update.UpdateID
+
+	if len(update.Data) > 0 && !update.isJSON {
+		return nil
+	}
+
+	switch update.ModelKey {
+	case "camera":
+		update.Camera = &Camera{}
+		return s.merge(update, update.Camera)
+	case "event":
+		update.Event = &ProtectEvent{}
+		if err := s.merge(update, update.Event); err != nil {
+			return err
+		}
+
+		if !update.Event.End.Val.IsZero() {
+			delete(s.objects, "event/"+update.ID) // finished events are not updated again.
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// merge applies an update to the last known state of an object, and decodes the result into v.
+func (s *protectState) merge(update *ProtectUpdate, v interface{}) error {
+	key := update.ModelKey + "/" + update.ID
+
+	object, ok := s.objects[key]
+	if !ok || update.Action == ProtectUpdateAdd {
+		object = make(map[string]interface{})
+	}
+
+	if update.Action == ProtectUpdateRemove {
+		delete(s.objects, key)
+	} else if len(update.Data) > 0 {
+		var changes map[string]interface{}
+		if err := json.Unmarshal(update.Data, &changes); err != nil {
+			return fmt.Errorf("decoding %s %s: %w", update.ModelKey, update.ID, err)
+		}
+
+		mergeJSON(object, changes)
+		s.objects[key] = object
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Errorf("encoding %s %s: %w", update.ModelKey, update.ID, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding %s %s: %w", update.ModelKey, update.ID, err)
+	}
+
+	return nil
+}
+
+// mergeJSON copies the values in src into dst. Nested objects are merged too.
+func mergeJSON(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if from, ok := value.(map[string]interface{}); ok {
+			if to, ok := dst[key].(map[string]interface{}); ok {
+				mergeJSON(to, from)
+				continue
+			}
+		}
+
+		dst[key] = value
+	}
+}
+
+// SubscribeProtect returns a channel of the changes pushed by the Protect NVR. The
+// bootstrap is requested to get the current state, then the updates websocket is
+// opened. If the websocket disconnects, the bootstrap is requested again and a new
+// websocket is opened with its lastUpdateId. The channel is closed when the context
+// is done. Updates are buffered; if the channel is full, the websocket is not read
+// until there is room, so no update is lost. An error is only returned if the first
+// bootstrap request fails, or if Config.RoundTripper can't dial websockets.
+func (u *Unifi) SubscribeProtect(ctx context.Context) (<-chan *ProtectUpdate, error) {
+	if _, err := u.websocketTransport(); err != nil {
+		return nil, err
+	}
+
+	state := &protectState{}
+	if err := u.GetDataCtx(ctx, APIProtectBootstrapPath, state); err != nil {
+		return nil, err
+	}
+
+	state.init()
+
+	updates := make(chan *ProtectUpdate, 100) //nolint:gomnd
+	reconnect := false
+
+	connect := func(ctx context.Context) (string, error) {
+		if reconnect {
+			fresh := &protectState{}
+			if err := u.GetDataCtx(ctx, APIProtectBootstrapPath, fresh); err != nil {
+				return "", err
+			}
+
+			state = fresh
+			state.init()
+		}
+
+		reconnect = true
+
+		return APIProtectUpdatesPath + "?" + url.Values{"lastUpdateId": {state.LastUpdateID}}.Encode(), nil
+	}
+
+	handle := func(packet []byte) {
+		update, err := decodeProtectPacket(packet)
+		if err == nil {
+			err = state.apply(update)
+		}
+
+		if err != nil {
+			u.logger().Error("decoding protect update", "error", err)
+			return
+		}
+
+		select {
+		case updates <- update:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(updates)
+		u.keepWebsocket(ctx, connect, handle)
+	}()
+
+	return updates, nil
+}
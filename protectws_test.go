@@ -0,0 +1,234 @@
+package unifi // nolint: testpackage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// protectFrameBytes encodes a Protect update frame, like the NVR does.
+func protectFrameBytes(t *testing.T, typ, format byte, payload []byte, deflate bool) []byte {
+	t.Helper()
+
+	header := []byte{typ, format, 0, 0, 0, 0, 0, 0}
+
+	if deflate {
+		var buf bytes.Buffer
+
+		w := zlib.NewWriter(&buf)
+		_, err := w.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		header[2] = 1
+		payload = buf.Bytes()
+	}
+
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+
+	return append(header, payload...)
+}
+
+// protectPacket encodes an update packet with a JSON action frame and a JSON payload frame.
+func protectPacket(t *testing.T, action, payload string, deflate bool) []byte {
+	t.Helper()
+
+	return append(protectFrameBytes(t, protectFrameAction, protectFormatJSON, []byte(action), deflate),
+		protectFrameBytes(t, protectFramePayload, protectFormatJSON, []byte(payload), deflate)...)
+}
+
+func TestDecodeProtectPacket(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	update, err := decodeProtectPacket(protectPacket(t,
+		`{"action":"update","newUpdateId":"u1","modelKey":"camera","id":"c1"}`, `{"isMotionDetected":true}`, true))
+	require.NoError(t, err)
+	a.Equal(ProtectUpdateUpdate, update.Action)
+	a.Equal("camera", update.ModelKey)
+	a.Equal("c1", update.ID)
+	a.Equal("u1", update.UpdateID)
+	a.JSONEq(`{"isMotionDetected":true}`, string(update.Data))
+
+	buffer := append(protectFrameBytes(t, protectFrameAction, protectFormatJSON,
+		[]byte(`{"action":"add","modelKey":"event","id":"e1"}`), false),
+		protectFrameBytes(t, protectFramePayload, 3, []byte{0xff, 0xd8}, false)...)
+	update, err = decodeProtectPacket(buffer)
+	require.NoError(t, err)
+	a.Equal([]byte{0xff, 0xd8}, update.Data)
+	a.False(update.isJSON)
+
+	_, err = decodeProtectPacket([]byte{1, 1, 0})
+	a.ErrorIs(err, ErrInvalidProtectPacket)
+
+	_, err = decodeProtectPacket(protectPacket(t, `{}`, `{}`, false)[:12])
+	a.ErrorIs(err, ErrInvalidProtectPacket, "a truncated payload must not panic.")
+
+	_, err = decodeProtectPacket(protectFrameBytes(t, protectFramePayload, protectFormatJSON, []byte(`{}`), false))
+	a.ErrorIs(err, ErrInvalidProtectPacket, "the first frame must be an action.")
+
+	_, err = decodeProtectPacket(protectFrameBytes(t, protectFrameAction, protectFormatJSON,
+		make([]byte, protectMaxFrameSize+1), true))
+	a.ErrorIs(err, ErrInvalidProtectPacket, "a frame must not inflate without a limit.")
+}
+
+func TestSubscribeProtect(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	connections := make(chan *http.Request, 2)
+	doorbell := "6183e5f40271f603e7000440"
+	reconnected := atomic.Bool{}
+
+	ws := websocket.Handler(func(conn *websocket.Conn) {
+		connections <- conn.Request()
+
+		if reconnected.Load() {
+			_ = websocket.Message.Send(conn, protectPacket(t,
+				`{"action":"remove","newUpdateId":"u5","modelKey":"camera","id":"`+doorbell+`"}`, `{}`, false))
+			<-conn.Request().Context().Done()
+
+			return
+		}
+
+		for _, packet := range [][]byte{
+			protectPacket(t, `{"action":"update","newUpdateId":"u1","modelKey":"camera","id":"`+doorbell+`"}`,
+				`{"isMotionDetected":true,"ledSettings":{"blinkRate":250}}`, true),
+			{0x01, 0x02}, // garbage is logged and skipped.
+			protectPacket(t, `{"action":"add","newUpdateId":"u2","modelKey":"event","id":"e1"}`,
+				`{"id":"e1","type":"motion","camera":"`+doorbell+`","start":1700000000000,"end":null,"score":0}`, false),
+			protectPacket(t, `{"action":"update","newUpdateId":"u3","modelKey":"event","id":"e1"}`,
+				`{"end":1700000005000,"score":72}`, false),
+			protectPacket(t, `{"action":"update","newUpdateId":"u4","modelKey":"nvr","id":"n1"}`, `{"uptime":5}`, false),
+		} {
+			if err := websocket.Message.Send(conn, packet); err != nil {
+				t.Error(err)
+			}
+		}
+
+		reconnected.Store(true)
+	})
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == APIPrefixNew+APIProtectUpdatesPath {
+			ws.ServeHTTP(w, r)
+		}
+	})
+	u.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := u.SubscribeProtect(ctx)
+	require.NoError(t, err)
+
+	req := <-connections
+	a.Equal("9f6c4d2b-8a3e-4c1f-b7d5-2e0a9c8b7f61", req.URL.Query().Get("lastUpdateId"))
+	a.Equal("key", req.Header.Get(APIKeyHeader))
+
+	update := <-updates
+	require.NotNil(t, update.Camera)
+	a.True(update.Camera.IsMotionDetected)
+	a.Equal(250, update.Camera.LedSettings.BlinkRate)
+	a.True(update.Camera.LedSettings.IsEnabled, "nested objects must be merged, not replaced.")
+	a.Equal("Front Door", update.Camera.Name, "updates must be applied to the bootstrap state.")
+
+	update = <-updates
+	require.NotNil(t, update.Event)
+	a.Equal(ProtectUpdateAdd, update.Action)
+	a.Equal(ProtectEventMotion, update.Event.Type)
+	a.Zero(update.Event.Duration())
+
+	update = <-updates
+	require.NotNil(t, update.Event)
+	a.Equal(ProtectEventMotion, update.Event.Type)
+	a.Equal(72, update.Event.Score)
+	a.Equal(5*time.Second, update.Event.Duration())
+
+	update = <-updates
+	a.Equal("nvr", update.ModelKey)
+	a.Nil(update.Camera)
+	a.JSONEq(`{"uptime":5}`, string(update.Data))
+
+	update = <-updates
+	a.Equal(ProtectUpdateRemove, update.Action)
+	require.NotNil(t, update.Camera)
+	a.Equal("Front Door", update.Camera.Name, "a reconnect must reload the bootstrap.")
+
+	req = <-connections
+	a.Equal("9f6c4d2b-8a3e-4c1f-b7d5-2e0a9c8b7f61", req.URL.Query().Get("lastUpdateId"))
+
+	cancel()
+
+	for range updates { //nolint:revive
+		// the channel must be closed when the context is done.
+	}
+}
+
+func TestSubscribeProtectFullChannel(t *testing.T) {
+	t.Parallel()
+
+	const count = 150 // more than the channel holds.
+
+	sent := make(chan struct{})
+	ws := websocket.Handler(func(conn *websocket.Conn) {
+		for i := 1; i <= count; i++ {
+			packet := protectPacket(t, fmt.Sprintf(`{"action":"update","newUpdateId":"u%d","modelKey":"nvr","id":"n1"}`, i),
+				fmt.Sprintf(`{"uptime":%d}`, i), false)
+			if err := websocket.Message.Send(conn, packet); err != nil {
+				t.Error(err)
+			}
+		}
+
+		close(sent)
+		<-conn.Request().Context().Done()
+	})
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == APIPrefixNew+APIProtectUpdatesPath {
+			ws.ServeHTTP(w, r)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := u.SubscribeProtect(ctx)
+	require.NoError(t, err)
+
+	<-sent
+	time.Sleep(50 * time.Millisecond) // let the channel fill up.
+
+	for i := 1; i <= count; i++ {
+		select {
+		case update := <-updates:
+			require.Equal(t, fmt.Sprintf("u%d", i), update.UpdateID, "updates must not be dropped when the channel is full.")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("update u%d was dropped", i)
+		}
+	}
+}
+
+func TestMergeJSON(t *testing.T) {
+	t.Parallel()
+
+	var dst, src map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":{"c":2,"d":3},"e":[1]}`), &dst))
+	require.NoError(t, json.Unmarshal([]byte(`{"b":{"c":4},"e":[2,3],"f":null}`), &src))
+	mergeJSON(dst, src)
+
+	out, err := json.Marshal(dst)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1,"b":{"c":4,"d":3},"e":[2,3],"f":null}`, string(out))
+}
@@ -0,0 +1,510 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// These are returned when a PTZ request is not sent because the camera can't do it.
+var (
+	ErrNotPTZ        = fmt.Errorf("camera is not a PTZ camera")
+	ErrPTZOutOfRange = fmt.Errorf("PTZ request is outside the camera's limits")
+)
+
+// PTZHomeSlot is the preset slot of a PTZ camera's home position.
+const PTZHomeSlot = -1
+
+// ptzSpeed is the pan, tilt and zoom speed sent with every move. The web UI uses the same.
+const ptzSpeed = 10
+
+// PTZPosition is where a PTZ camera is pointing. Pan and Tilt are degrees, and Zoom
+// is the zoom ratio, like 1 to 22 for a 22x camera. The limits are in the camera's
+// FeatureFlags: Pan.Degrees, Tilt.Degrees and Zoom.Degrees.
+type PTZPosition struct {
+	Pan  float64
+	Tilt float64
+	Zoom float64
+}
+
+// PTZPreset is a saved position on a PTZ camera.
+type PTZPreset struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slot int    `json:"slot"`
+	PTZ  struct {
+		Pan  FlexInt `json:"pan"`
+		Tilt FlexInt `json:"tilt"`
+		Zoom FlexInt `json:"zoom"`
+	} `json:"ptz"`
+}
+
+// PTZPatrol is a tour of presets on a PTZ camera.
+type PTZPatrol struct {
+	ID                    string `json:"id"`
+	Name                  string `json:"name"`
+	Slot                  int    `json:"slot"`
+	Presets               []int  `json:"presets"`
+	PresetMovementSpeed   int    `json:"presetMovementSpeed"`
+	PresetDurationSeconds int    `json:"presetDurationSeconds"`
+	Camera                string `json:"camera"`
+}
+
+// ptzSteps is a PTZ camera's motor position, in steps.
+type ptzSteps struct {
+	Pan   float64 `json:"pan"`
+	Tilt  float64 `json:"tilt"`
+	Zoom  float64 `json:"zoom"`
+	Focus float64 `json:"focus"`
+}
+
+// ptzAxis converts between degrees and motor steps on one axis of a PTZ camera.
+type ptzAxis struct {
+	name    string
+	steps   FeatureRange
+	degrees FeatureRange
+}
+
+func ptzAxes(camera *Camera) (pan, tilt, zoom ptzAxis) {
+	flags := camera.FeatureFlags
+
+	return ptzAxis{name: "pan", steps: flags.Pan.Steps, degrees: flags.Pan.Degrees},
+		ptzAxis{name: "tilt", steps: flags.Tilt.Steps, degrees: flags.Tilt.Degrees},
+		ptzAxis{name: "zoom", steps: flags.Zoom.Steps, degrees: flags.Zoom.Degrees}
+}
+
+// stepsPerDegree returns zero if the camera does not have this axis.
+func (a ptzAxis) stepsPerDegree() float64 {
+	if a.degrees.Max.Val <= a.degrees.Min.Val {
+		return 0
+	}
+
+	return (a.steps.Max.Val - a.steps.Min.Val) / (a.degrees.Max.Val - a.degrees.Min.Val)
+}
+
+// round rounds steps to a multiple of the axis' step size.
+func (a ptzAxis) round(steps float64) float64 {
+	if a.steps.Step.Val <= 0 {
+		return math.Round(steps)
+	}
+
+	return math.Round(steps/a.steps.Step.Val) * a.steps.Step.Val
+}
+
+// wraps is true if the axis turns all the way around, so 0 and 360 degrees are the same.
+func (a ptzAxis) wraps() bool {
+	return a.degrees.Max.Val-a.degrees.Min.Val >= 360 //nolint:gomnd
+}
+
+// relative converts a move in degrees from the current position, in steps, to a move in steps.
+func (a ptzAxis) relative(current, degrees float64) (float64, error) {
+	if degrees == 0 {
+		return 0, nil
+	}
+
+	scale := a.stepsPerDegree()
+	if scale == 0 {
+		return 0, fmt.Errorf("%w: camera cannot %s", ErrPTZOutOfRange, a.name)
+	}
+
+	if span := a.degrees.Max.Val - a.degrees.Min.Val; math.Abs(degrees) > span {
+		return 0, fmt.Errorf("%w: %s of %g degrees is more than %g", ErrPTZOutOfRange, a.name, degrees, span)
+	}
+
+	steps := a.round(degrees * scale)
+	if steps == 0 {
+		return 0, fmt.Errorf("%w: %s of %g degrees is less than one step of %g degrees",
+			ErrPTZOutOfRange, a.name, degrees, a.steps.Step.Val/scale)
+	}
+
+	if to := current + steps; !a.wraps() && (to < a.steps.Min.Val || to > a.steps.Max.Val) {
+		return 0, fmt.Errorf("%w: %s of %g degrees from %g is not from %g to %g", ErrPTZOutOfRange,
+			a.name, degrees, a.toDegrees(current), a.degrees.Min.Val, a.degrees.Max.Val)
+	}
+
+	return steps, nil
+}
+
+// absolute converts a position in degrees to a position in steps.
+func (a ptzAxis) absolute(degrees float64) (float64, error) {
+	scale := a.stepsPerDegree()
+	if scale == 0 {
+		return 0, fmt.Errorf("%w: camera cannot %s", ErrPTZOutOfRange, a.name)
+	}
+
+	if degrees < a.degrees.Min.Val || degrees > a.degrees.Max.Val {
+		return 0, fmt.Errorf("%w: %s of %g is not from %g to %g",
+			ErrPTZOutOfRange, a.name, degrees, a.degrees.Min.Val, a.degrees.Max.Val)
+	}
+
+	return a.round((degrees-a.degrees.Min.Val)*scale + a.steps.Min.Val), nil
+}
+
+// toDegrees converts a position in steps to a position in degrees.
+func (a ptzAxis) toDegrees(steps float64) float64 {
+	scale := a.stepsPerDegree()
+	if scale == 0 {
+		return 0
+	}
+
+	return (steps-a.steps.Min.Val)/scale + a.degrees.Min.Val
+}
+
+// delta returns the steps to move from one position to another, the short way around
+// if the axis wraps.
+func (a ptzAxis) delta(from, to float64) float64 {
+	delta := to - from
+
+	if span := a.steps.Max.Val - a.steps.Min.Val; a.wraps() && math.Abs(delta) > span/2 {
+		delta -= math.Copysign(span, delta)
+	}
+
+	return delta
+}
+
+func checkPTZ(camera *Camera) error {
+	if camera == nil || !camera.FeatureFlags.IsPtz {
+		name := ""
+		if camera != nil {
+			name = camera.Name
+		}
+
+		return fmt.Errorf("%w: %s", ErrNotPTZ, name)
+	}
+
+	return nil
+}
+
+// GetPTZPosition returns where a PTZ camera is pointing.
+func (u *Unifi) GetPTZPosition(camera *Camera) (*PTZPosition, error) {
+	return u.GetPTZPositionCtx(context.Background(), camera)
+}
+
+// GetPTZPositionCtx is the same as GetPTZPosition, but uses the provided context.
+func (u *Unifi) GetPTZPositionCtx(ctx context.Context, camera *Camera) (*PTZPosition, error) {
+	steps, err := u.getPTZSteps(ctx, camera)
+	if err != nil {
+		return nil, err
+	}
+
+	pan, tilt, zoom := ptzAxes(camera)
+
+	return &PTZPosition{
+		Pan:  pan.toDegrees(steps.Pan),
+		Tilt: tilt.toDegrees(steps.Tilt),
+		Zoom: zoom.toDegrees(steps.Zoom),
+	}, nil
+}
+
+func (u *Unifi) getPTZSteps(ctx context.Context, camera *Camera) (*ptzSteps, error) {
+	if err := checkPTZ(camera); err != nil {
+		return nil, err
+	}
+
+	var steps ptzSteps
+
+	if err := u.GetDataCtx(ctx, fmt.Sprintf(APIProtectPTZPositionPath, camera.ID), &steps); err != nil {
+		return nil, err
+	}
+
+	return &steps, nil
+}
+
+// MovePTZ moves a PTZ camera from where it is pointing. Pan and Tilt are degrees to
+// move, and Zoom is the change in zoom ratio. The camera's position is requested first,
+// and the move is rejected if it would end outside the camera's limits, or if it's
+// smaller than one motor step.
+func (u *Unifi) MovePTZ(camera *Camera, move PTZPosition) error {
+	return u.MovePTZCtx(context.Background(), camera, move)
+}
+
+// MovePTZCtx is the same as MovePTZ, but uses the provided context.
+func (u *Unifi) MovePTZCtx(ctx context.Context, camera *Camera, move PTZPosition) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	if move == (PTZPosition{}) {
+		return nil
+	}
+
+	// The limits apply to where the camera ends up, so find out where it is first.
+	current, err := u.getPTZSteps(ctx, camera)
+	if err != nil {
+		return err
+	}
+
+	pan, tilt, zoom := ptzAxes(camera)
+
+	panSteps, err := pan.relative(current.Pan, move.Pan)
+	if err != nil {
+		return err
+	}
+
+	tiltSteps, err := tilt.relative(current.Tilt, move.Tilt)
+	if err != nil {
+		return err
+	}
+
+	zoomSteps := 0.0
+
+	if move.Zoom != 0 {
+		if zoomSteps, err = zoom.absolute(zoom.toDegrees(current.Zoom) + move.Zoom); err != nil {
+			return err
+		}
+	}
+
+	if panSteps != 0 || tiltSteps != 0 {
+		if err := u.ptzRelative(ctx, camera, panSteps, tiltSteps); err != nil {
+			return err
+		}
+	}
+
+	if move.Zoom != 0 {
+		return u.ptzZoom(ctx, camera, zoomSteps)
+	}
+
+	return nil
+}
+
+// MovePTZTo points a PTZ camera at a position. The position is checked against the
+// camera's limits, so Zoom must be set too; start from GetPTZPosition to change only
+// some axes. Pan takes the short way around on cameras that turn all the way.
+func (u *Unifi) MovePTZTo(camera *Camera, position PTZPosition) error {
+	return u.MovePTZToCtx(context.Background(), camera, position)
+}
+
+// MovePTZToCtx is the same as MovePTZTo, but uses the provided context.
+func (u *Unifi) MovePTZToCtx(ctx context.Context, camera *Camera, position PTZPosition) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	pan, tilt, zoom := ptzAxes(camera)
+
+	panSteps, err := pan.absolute(position.Pan)
+	if err != nil {
+		return err
+	}
+
+	tiltSteps, err := tilt.absolute(position.Tilt)
+	if err != nil {
+		return err
+	}
+
+	zoomSteps, err := zoom.absolute(position.Zoom)
+	if err != nil {
+		return err
+	}
+
+	// The NVR only moves pan and tilt relatively, so find out where the camera is first.
+	current, err := u.getPTZSteps(ctx, camera)
+	if err != nil {
+		return err
+	}
+
+	panDelta := pan.round(pan.delta(current.Pan, panSteps))
+	tiltDelta := tilt.round(tilt.delta(current.Tilt, tiltSteps))
+
+	if panDelta != 0 || tiltDelta != 0 {
+		if err := u.ptzRelative(ctx, camera, panDelta, tiltDelta); err != nil {
+			return err
+		}
+	}
+
+	return u.ptzZoom(ctx, camera, zoomSteps)
+}
+
+func (u *Unifi) ptzRelative(ctx context.Context, camera *Camera, pan, tilt float64) error {
+	return u.ptzMove(ctx, camera, "relative", map[string]interface{}{
+		"panPos":    pan,
+		"tiltPos":   tilt,
+		"panSpeed":  ptzSpeed,
+		"tiltSpeed": ptzSpeed,
+		"scale":     0,
+	})
+}
+
+func (u *Unifi) ptzZoom(ctx context.Context, camera *Camera, zoom float64) error {
+	return u.ptzMove(ctx, camera, "zoom", map[string]interface{}{
+		"zoomPos":   zoom,
+		"zoomSpeed": ptzSpeed,
+	})
+}
+
+func (u *Unifi) ptzMove(ctx context.Context, camera *Camera, moveType string, payload map[string]interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{"type": moveType, "payload": payload})
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	_, err = u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectPTZMovePath, camera.ID), string(data))
+
+	return err
+}
+
+// GetPTZPresets returns the saved positions on a PTZ camera.
+func (u *Unifi) GetPTZPresets(camera *Camera) ([]*PTZPreset, error) {
+	return u.GetPTZPresetsCtx(context.Background(), camera)
+}
+
+// GetPTZPresetsCtx is the same as GetPTZPresets, but uses the provided context.
+func (u *Unifi) GetPTZPresetsCtx(ctx context.Context, camera *Camera) ([]*PTZPreset, error) {
+	if err := checkPTZ(camera); err != nil {
+		return nil, err
+	}
+
+	var presets []*PTZPreset
+
+	if err := u.GetDataCtx(ctx, fmt.Sprintf(APIProtectPTZPresetsPath, camera.ID), &presets); err != nil {
+		return nil, err
+	}
+
+	return presets, nil
+}
+
+// GotoPTZPreset moves a PTZ camera to a saved position. Use PTZHomeSlot for the home position.
+// Other slots are checked against the camera's presets first.
+func (u *Unifi) GotoPTZPreset(camera *Camera, slot int) error {
+	return u.GotoPTZPresetCtx(context.Background(), camera, slot)
+}
+
+// GotoPTZPresetCtx is the same as GotoPTZPreset, but uses the provided context.
+func (u *Unifi) GotoPTZPresetCtx(ctx context.Context, camera *Camera, slot int) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	if slot != PTZHomeSlot {
+		presets, err := u.GetPTZPresetsCtx(ctx, camera)
+		if err != nil {
+			return err
+		}
+
+		if !slices.ContainsFunc(presets, func(p *PTZPreset) bool { return p.Slot == slot }) {
+			return fmt.Errorf("%w: no preset in slot %d", ErrPTZOutOfRange, slot)
+		}
+	}
+
+	_, err := u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectPTZGotoPath, camera.ID, slot))
+
+	return err
+}
+
+// SavePTZPreset saves where a PTZ camera is pointing as a new preset, and returns it.
+func (u *Unifi) SavePTZPreset(camera *Camera, name string) (*PTZPreset, error) {
+	return u.SavePTZPresetCtx(context.Background(), camera, name)
+}
+
+// SavePTZPresetCtx is the same as SavePTZPreset, but uses the provided context.
+func (u *Unifi) SavePTZPresetCtx(ctx context.Context, camera *Camera, name string) (*PTZPreset, error) {
+	if err := checkPTZ(camera); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	body, err := u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectPTZPresetsPath, camera.ID), string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var preset PTZPreset
+
+	if err := json.Unmarshal(body, &preset); err != nil {
+		return nil, fmt.Errorf("json unmarshal: %w", err)
+	}
+
+	return &preset, nil
+}
+
+// DeletePTZPreset removes a saved position from a PTZ camera. The home position cannot be removed.
+func (u *Unifi) DeletePTZPreset(camera *Camera, slot int) error {
+	return u.DeletePTZPresetCtx(context.Background(), camera, slot)
+}
+
+// DeletePTZPresetCtx is the same as DeletePTZPreset, but uses the provided context.
+func (u *Unifi) DeletePTZPresetCtx(ctx context.Context, camera *Camera, slot int) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	if slot < 0 {
+		return fmt.Errorf("%w: preset slot %d", ErrPTZOutOfRange, slot)
+	}
+
+	_, err := u.DeleteJSONCtx(ctx, fmt.Sprintf(APIProtectPTZPresetPath, camera.ID, slot))
+
+	return err
+}
+
+// GetPTZPatrols returns the preset tours on a PTZ camera.
+func (u *Unifi) GetPTZPatrols(camera *Camera) ([]*PTZPatrol, error) {
+	return u.GetPTZPatrolsCtx(context.Background(), camera)
+}
+
+// GetPTZPatrolsCtx is the same as GetPTZPatrols, but uses the provided context.
+func (u *Unifi) GetPTZPatrolsCtx(ctx context.Context, camera *Camera) ([]*PTZPatrol, error) {
+	if err := checkPTZ(camera); err != nil {
+		return nil, err
+	}
+
+	var patrols []*PTZPatrol
+
+	if err := u.GetDataCtx(ctx, fmt.Sprintf(APIProtectPTZPatrolsPath, camera.ID), &patrols); err != nil {
+		return nil, err
+	}
+
+	return patrols, nil
+}
+
+// StartPTZPatrol starts a preset tour on a PTZ camera. The camera must support preset
+// tours, and the slot is checked against the camera's patrols first.
+func (u *Unifi) StartPTZPatrol(camera *Camera, slot int) error {
+	return u.StartPTZPatrolCtx(context.Background(), camera, slot)
+}
+
+// StartPTZPatrolCtx is the same as StartPTZPatrol, but uses the provided context.
+func (u *Unifi) StartPTZPatrolCtx(ctx context.Context, camera *Camera, slot int) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	if !camera.FeatureFlags.PresetTour {
+		return fmt.Errorf("%w: camera %s has no preset tours", ErrPTZOutOfRange, camera.Name)
+	}
+
+	patrols, err := u.GetPTZPatrolsCtx(ctx, camera)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(patrols, func(p *PTZPatrol) bool { return p.Slot == slot }) {
+		return fmt.Errorf("%w: no patrol in slot %d", ErrPTZOutOfRange, slot)
+	}
+
+	_, err = u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectPTZPatrolStartPath, camera.ID, slot))
+
+	return err
+}
+
+// StopPTZPatrol stops the running preset tour on a PTZ camera.
+func (u *Unifi) StopPTZPatrol(camera *Camera) error {
+	return u.StopPTZPatrolCtx(context.Background(), camera)
+}
+
+// StopPTZPatrolCtx is the same as StopPTZPatrol, but uses the provided context.
+func (u *Unifi) StopPTZPatrolCtx(ctx context.Context, camera *Camera) error {
+	if err := checkPTZ(camera); err != nil {
+		return err
+	}
+
+	_, err := u.PostJSONCtx(ctx, fmt.Sprintf(APIProtectPTZPatrolStopPath, camera.ID))
+
+	return err
+}
@@ -0,0 +1,123 @@
+package unifi // nolint: testpackage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPTZ(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var camera Camera
+
+	require.NoError(t, json.Unmarshal(cameraSample, &camera))
+
+	prefix := APIPrefixNew + "/api/cameras/" + camera.ID
+	requests := make(chan string, 10)
+	tilt := atomic.Int32{} // the tilt motor position, in steps.
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		path := strings.TrimPrefix(r.URL.Path, prefix)
+
+		switch path {
+		case "/ptz/position":
+			_, _ = fmt.Fprintf(w, `{"pan":35000,"tilt":%d,"zoom":0,"focus":100}`, tilt.Load())
+			return
+		case "/ptz/preset":
+			if r.Method == http.MethodPost {
+				_, _ = w.Write([]byte(`{"id":"p3","name":"Gate","slot":3,"ptz":{"pan":100,"tilt":200,"zoom":0}}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`[{"id":"p0","name":"Drive","slot":0,"ptz":{"pan":0,"tilt":0,"zoom":0}}]`))
+
+			return
+		case "/ptz/patrol":
+			_, _ = w.Write([]byte(`[{"id":"t0","name":"Yard","slot":0,"presets":[0,3],"presetDurationSeconds":10}]`))
+			return
+		}
+
+		requests <- r.Method + " " + path + " " + string(body)
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	require.NoError(t, u.MovePTZ(&camera, PTZPosition{Pan: 15, Tilt: -5}))
+	method, body, _ := strings.Cut(<-requests, " /move ")
+	a.Equal(http.MethodPost, method)
+	a.JSONEq(`{"type":"relative","payload":{"panPos":1500,"tiltPos":-500,"panSpeed":10,"tiltSpeed":10,"scale":0}}`, body)
+
+	a.ErrorIs(u.MovePTZ(&camera, PTZPosition{Pan: 0.01}), ErrPTZOutOfRange, "moves under one step must be rejected.")
+	a.ErrorIs(u.MovePTZ(&camera, PTZPosition{Tilt: 200}), ErrPTZOutOfRange)
+	a.ErrorIs(u.MovePTZ(&camera, PTZPosition{Zoom: -1}), ErrPTZOutOfRange, "zoom must not go below 1x.")
+
+	tilt.Store(8500) // 85 degrees, on a tilt axis from -20 to 90 degrees.
+	a.ErrorIs(u.MovePTZ(&camera, PTZPosition{Tilt: 20}), ErrPTZOutOfRange, "the move must end inside the limits.")
+	require.NoError(t, u.MovePTZ(&camera, PTZPosition{Tilt: -20, Pan: 20}), "pan wraps around, so it has no limit.")
+	_, body, _ = strings.Cut(<-requests, " /move ")
+	a.JSONEq(`{"type":"relative","payload":{"panPos":2000,"tiltPos":-2000,"panSpeed":10,"tiltSpeed":10,"scale":0}}`, body)
+	tilt.Store(0)
+
+	require.NoError(t, u.MovePTZTo(&camera, PTZPosition{Pan: 10, Tilt: 45, Zoom: 2}))
+	_, body, _ = strings.Cut(<-requests, " /move ")
+	a.JSONEq(`{"type":"relative","payload":{"panPos":2000,"tiltPos":4500,"panSpeed":10,"tiltSpeed":10,"scale":0}}`,
+		body, "pan must take the short way around, from 350 to 10 degrees.")
+	_, body, _ = strings.Cut(<-requests, " /move ")
+	a.JSONEq(`{"type":"zoom","payload":{"zoomPos":100,"zoomSpeed":10}}`, body)
+
+	a.ErrorIs(u.MovePTZTo(&camera, PTZPosition{Tilt: 95, Zoom: 1}), ErrPTZOutOfRange)
+	a.ErrorIs(u.MovePTZTo(&camera, PTZPosition{Zoom: 0}), ErrPTZOutOfRange)
+
+	position, err := u.GetPTZPosition(&camera)
+	require.NoError(t, err)
+	a.InDelta(350, position.Pan, 0.001)
+	a.InDelta(0, position.Tilt, 0.001)
+	a.InDelta(1, position.Zoom, 0.001)
+
+	presets, err := u.GetPTZPresets(&camera)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	a.Equal("Drive", presets[0].Name)
+
+	preset, err := u.SavePTZPreset(&camera, "Gate")
+	require.NoError(t, err)
+	a.Equal(3, preset.Slot)
+	a.EqualValues(200, preset.PTZ.Tilt.Val)
+
+	require.NoError(t, u.GotoPTZPreset(&camera, PTZHomeSlot))
+	a.Equal("POST /ptz/goto/-1 ", <-requests)
+	require.NoError(t, u.GotoPTZPreset(&camera, 0))
+	a.Equal("POST /ptz/goto/0 ", <-requests)
+	a.ErrorIs(u.GotoPTZPreset(&camera, 5), ErrPTZOutOfRange, "slots without a preset must be rejected.")
+	require.NoError(t, u.DeletePTZPreset(&camera, 3))
+	a.Equal("DELETE /ptz/preset/3 ", <-requests)
+	a.ErrorIs(u.DeletePTZPreset(&camera, PTZHomeSlot), ErrPTZOutOfRange)
+
+	patrols, err := u.GetPTZPatrols(&camera)
+	require.NoError(t, err)
+	require.Len(t, patrols, 1)
+	a.Equal([]int{0, 3}, patrols[0].Presets)
+
+	a.ErrorIs(u.StartPTZPatrol(&camera, 0), ErrPTZOutOfRange, "the camera must support preset tours.")
+
+	camera.FeatureFlags.PresetTour = true
+	require.NoError(t, u.StartPTZPatrol(&camera, 0))
+	a.Equal("POST /ptz/patrol/start/0 ", <-requests)
+	a.ErrorIs(u.StartPTZPatrol(&camera, 1), ErrPTZOutOfRange, "slots without a patrol must be rejected.")
+	require.NoError(t, u.StopPTZPatrol(&camera))
+	a.Equal("POST /ptz/patrol/stop ", <-requests)
+
+	camera.FeatureFlags.IsPtz = false
+	a.ErrorIs(u.GotoPTZPreset(&camera, 0), ErrNotPTZ)
+	a.ErrorIs(u.MovePTZ(nil, PTZPosition{Pan: 1}), ErrNotPTZ)
+	a.Equal(APIProtectPTZGotoPath, pathTemplate(prefix+"/ptz/goto/-1"))
+}
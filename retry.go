@@ -0,0 +1,150 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultRetryStatusCodes are the HTTP status codes retried when
+// RetryPolicy.StatusCodes is empty. These are returned by overloaded
+// controllers, and by consoles that are restarting or upgrading.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryMethods are the HTTP methods retried when RetryPolicy.Methods is
+// empty. Only requests that are safe to send twice are retried by default;
+// the Protect clip prepare and download requests are both GET requests.
+var DefaultRetryMethods = []string{http.MethodGet, http.MethodHead}
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+// Provide one in Config.Retry; a nil policy disables retries. By default only GET
+// and HEAD requests are retried, including the Protect clip prepare and download
+// requests, which are known to fail with 500 errors under load. Requests that
+// change something, like device commands or unlocking a door, are not retried
+// unless their method is in Methods, because a failed request may have been applied.
+// Websocket subscriptions use MinBackoff and MaxBackoff between reconnects,
+// and reconnect until their context is done.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. 0 or 1 disables retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles every attempt. Default: 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. Default: 30s.
+	MaxBackoff time.Duration
+	// Jitter randomly shortens each delay by up to this fraction (0.0-1.0).
+	Jitter float64
+	// StatusCodes are the HTTP status codes to retry. Default: DefaultRetryStatusCodes.
+	StatusCodes []int
+	// Methods are the HTTP methods to retry. Default: DefaultRetryMethods.
+	// Add POST, PUT, PATCH or DELETE only if replaying those requests is safe.
+	Methods []string
+	// Retryable decides if a request error (no HTTP response) is retried.
+	// Default: every error is retried unless the request's context is done.
+	Retryable func(error) bool
+}
+
+// retry returns true and the delay before the next attempt if a request should be retried.
+// attempt is the number of the attempt that just finished, starting at 1.
+func (r *RetryPolicy) retry(ctx context.Context, method string, attempt, status int, err error) (time.Duration, bool) {
+	if r == nil || attempt >= r.MaxAttempts || ctx.Err() != nil || err == nil {
+		return 0, false
+	}
+
+	if !r.retryMethod(method) || !r.retryable(status, err) {
+		return 0, false
+	}
+
+	return r.backoff(attempt), true
+}
+
+func (r *RetryPolicy) retryMethod(method string) bool {
+	methods := r.Methods
+	if len(methods) == 0 {
+		methods = DefaultRetryMethods
+	}
+
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RetryPolicy) retryable(status int, err error) bool {
+	if status == 0 {
+		if r.Retryable != nil {
+			return r.Retryable(err)
+		}
+
+		return !errors.Is(err, context.Canceled)
+	}
+
+	codes := r.StatusCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryStatusCodes
+	}
+
+	for _, code := range codes {
+		if code == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the exponential, jittered delay after an attempt.
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	minDelay, maxDelay := r.MinBackoff, r.MaxBackoff
+	if minDelay <= 0 {
+		minDelay = defaultMinBackoff
+	}
+
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxBackoff
+	}
+
+	delay := minDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if r.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * r.Jitter * float64(delay)) //nolint:gosec
+	}
+
+	return delay
+}
+
+// sleepCtx waits for a duration or until the context is done.
+func sleepCtx(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
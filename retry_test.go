@@ -0,0 +1,81 @@
+package unifi // nolint: testpackage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var (
+		requests atomic.Int32
+		retries  atomic.Int32
+		notFound atomic.Bool
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := requests.Add(1); {
+		case notFound.Load():
+			w.WriteHeader(http.StatusNotFound)
+		case n <= 2:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	u := newUnifi(&Config{
+		URL:   srv.URL,
+		Retry: &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, Jitter: 0.5},
+		DebugLog: func(msg string, _ ...interface{}) {
+			if strings.HasPrefix(msg, "Retrying") {
+				retries.Add(1)
+			}
+		},
+	}, nil)
+
+	a.NoError(u.GetData(APISiteList, &struct{}{}), "the third attempt must succeed")
+	a.EqualValues(3, requests.Load())
+	a.EqualValues(2, retries.Load(), "every retry must be logged")
+
+	// POST is not retried by default.
+	requests.Store(0)
+	a.ErrorIs(u.GetData(APISiteList, &struct{}{}, `{"a":"b"}`), ErrInvalidStatusCode)
+	a.EqualValues(1, requests.Load(), "a POST must not be retried unless it's in Methods")
+
+	requests.Store(0)
+	u.Retry.Methods = []string{http.MethodGet, http.MethodPost}
+	a.NoError(u.GetData(APISiteList, &struct{}{}, `{"a":"b"}`), "a POST in Methods must be retried")
+	a.EqualValues(3, requests.Load())
+
+	// Not found is not retried.
+	notFound.Store(true)
+
+	a.ErrorIs(u.GetData(APISiteList, &struct{}{}), ErrInvalidStatusCode)
+	a.EqualValues(4, requests.Load(), "a 404 must not be retried")
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	r := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	a.Equal(time.Second, r.backoff(1))
+	a.Equal(2*time.Second, r.backoff(2))
+	a.Equal(4*time.Second, r.backoff(3))
+	a.Equal(5*time.Second, r.backoff(4), "backoff must be capped at MaxBackoff")
+
+	r.Jitter = 1
+	for i := 0; i < 100; i++ {
+		a.LessOrEqual(r.backoff(2), 2*time.Second, "jitter must not extend the delay")
+	}
+}
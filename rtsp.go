@@ -0,0 +1,182 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// ErrNoChannel is returned when a camera does not have the requested channel.
+var ErrNoChannel = fmt.Errorf("camera has no such channel")
+
+// These are the NVR's RTSP ports, used when the bootstrap does not provide them.
+const (
+	DefaultRTSPSPort = 7441
+	DefaultRTSPPort  = 7447
+)
+
+// StreamURL is where to watch one of a camera's channels over RTSP.
+type StreamURL struct {
+	ChannelID int
+	Name      string // High, Medium or Low.
+	Width     int
+	Height    int
+	FPS       int
+	Alias     string
+	// RTSPS is the encrypted stream, like rtsps://host:7441/alias.
+	RTSPS *url.URL
+	// RTSP is the unencrypted stream, like rtsp://host:7447/alias.
+	RTSP *url.URL
+}
+
+// EnableRTSP turns on RTSP streaming for a camera channel, and returns the updated
+// camera. The channel's RtspAlias is set by the NVR; use StreamURLs to get the URLs.
+func (u *Unifi) EnableRTSP(cameraID string, channelID int) (*Camera, error) {
+	return u.EnableRTSPCtx(context.Background(), cameraID, channelID)
+}
+
+// EnableRTSPCtx is the same as EnableRTSP, but uses the provided context.
+func (u *Unifi) EnableRTSPCtx(ctx context.Context, cameraID string, channelID int) (*Camera, error) {
+	return u.setRTSP(ctx, cameraID, channelID, true)
+}
+
+// DisableRTSP turns off RTSP streaming for a camera channel, and returns the updated camera.
+func (u *Unifi) DisableRTSP(cameraID string, channelID int) (*Camera, error) {
+	return u.DisableRTSPCtx(context.Background(), cameraID, channelID)
+}
+
+// DisableRTSPCtx is the same as DisableRTSP, but uses the provided context.
+func (u *Unifi) DisableRTSPCtx(ctx context.Context, cameraID string, channelID int) (*Camera, error) {
+	return u.setRTSP(ctx, cameraID, channelID, false)
+}
+
+// setRTSP sends every channel back to the NVR with the one channel changed; the NVR
+// replaces the whole list. The channels are copied from the camera's raw JSON, so
+// fields this library does not know, and null values, are sent back unchanged.
+func (u *Unifi) setRTSP(ctx context.Context, cameraID string, channelID int, enabled bool) (*Camera, error) {
+	apiPath := fmt.Sprintf(APIProtectCameraPath, cameraID)
+
+	data, err := u.GetJSONCtx(ctx, apiPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var camera struct {
+		Name     string                       `json:"name"`
+		Channels []map[string]json.RawMessage `json:"channels"`
+	}
+
+	if err := json.Unmarshal(data, &camera); err != nil {
+		return nil, fmt.Errorf("json unmarshal: %w", err)
+	}
+
+	found := false
+
+	for _, channel := range camera.Channels {
+		var id int
+		if err := json.Unmarshal(channel["id"], &id); err != nil || id != channelID {
+			continue
+		}
+
+		found = true
+		channel["isRtspEnabled"] = json.RawMessage(strconv.FormatBool(enabled))
+
+		if alias := channel["rtspAlias"]; enabled && (alias == nil || string(alias) == `""`) {
+			channel["rtspAlias"] = json.RawMessage("null") // the NVR creates the alias.
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s (%s) channel %d", ErrNoChannel, camera.Name, cameraID, channelID)
+	}
+
+	if data, err = json.Marshal(map[string]interface{}{"channels": camera.Channels}); err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	updated := &Camera{}
+	if err := u.PatchDataCtx(ctx, apiPath, updated, string(data)); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range updated.Channels {
+		if channel.ID != channelID {
+			continue
+		}
+
+		if channel.IsRtspEnabled != enabled || (enabled && channel.RtspAlias == "") {
+			return updated, fmt.Errorf("%w: channel %d isRtspEnabled is %v, alias %q",
+				ErrCameraNotUpdated, channelID, channel.IsRtspEnabled, channel.RtspAlias)
+		}
+
+		return updated, nil
+	}
+
+	return updated, fmt.Errorf("%w: channel %d is missing", ErrCameraNotUpdated, channelID)
+}
+
+// StreamURLs returns the RTSP and RTSPS URLs for each of a camera's channels that
+// has RTSP enabled. The ports are from the NVR in the bootstrap, and the host is
+// the one in Config.URL, so the URLs work from wherever this library does.
+func (u *Unifi) StreamURLs(camera *Camera) ([]*StreamURL, error) {
+	return u.StreamURLsCtx(context.Background(), camera)
+}
+
+// StreamURLsCtx is the same as StreamURLs, but uses the provided context.
+func (u *Unifi) StreamURLsCtx(ctx context.Context, camera *Camera) ([]*StreamURL, error) {
+	bootstrap, err := u.GetProtectBootstrapCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	host := ""
+	if parsed, err := url.Parse(u.URL); err == nil {
+		host = parsed.Hostname()
+	}
+
+	return bootstrap.NVR.StreamURLs(camera, host), nil
+}
+
+// StreamURLs returns the RTSP and RTSPS URLs for each of a camera's channels that has
+// RTSP enabled. The host is the NVR's address; NVR.Host is used if it's empty.
+func (n *NVR) StreamURLs(camera *Camera, host string) []*StreamURL {
+	rtsps, rtsp := DefaultRTSPSPort, DefaultRTSPPort
+
+	if n != nil {
+		if host == "" {
+			host = n.Host
+		}
+
+		if n.Ports.Rtsps != 0 {
+			rtsps = n.Ports.Rtsps
+		}
+
+		if n.Ports.Rtsp != 0 {
+			rtsp = n.Ports.Rtsp
+		}
+	}
+
+	urls := []*StreamURL{}
+
+	for _, channel := range camera.Channels {
+		if !channel.IsRtspEnabled || channel.RtspAlias == "" {
+			continue
+		}
+
+		urls = append(urls, &StreamURL{
+			ChannelID: channel.ID,
+			Name:      channel.Name,
+			Width:     channel.Width,
+			Height:    channel.Height,
+			FPS:       channel.Fps,
+			Alias:     channel.RtspAlias,
+			RTSPS:     &url.URL{Scheme: "rtsps", Host: net.JoinHostPort(host, strconv.Itoa(rtsps)), Path: "/" + channel.RtspAlias},
+			RTSP:      &url.URL{Scheme: "rtsp", Host: net.JoinHostPort(host, strconv.Itoa(rtsp)), Path: "/" + channel.RtspAlias},
+		})
+	}
+
+	return urls
+}
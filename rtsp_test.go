@@ -0,0 +1,109 @@
+package unifi // nolint: testpackage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableRTSP(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var sample map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(cameraSample, &sample))
+
+	id, _ := sample["id"].(string)
+	channels, _ := sample["channels"].([]interface{})
+	medium, _ := channels[1].(map[string]interface{})
+	medium["minClientAdaptiveBitRate"] = nil
+	medium["futureSetting"] = "kept"
+
+	bodies := make(chan []byte, 1)
+	ignore := false // the NVR ignores the change when true.
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == APIPrefixNew+"/api/cameras/"+id:
+			_ = json.NewEncoder(w).Encode(sample)
+		case r.Method == http.MethodPatch && r.URL.Path == APIPrefixNew+"/api/cameras/"+id:
+			var body map[string][]map[string]interface{}
+
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			bodies <- data
+
+			if !ignore {
+				for _, channel := range body["channels"] {
+					if channel["isRtspEnabled"] == true && channel["rtspAlias"] == nil {
+						channel["rtspAlias"] = "Zr3bLq0sM1Yc9TfA"
+					}
+				}
+
+				sample["channels"] = body["channels"]
+			}
+
+			_ = json.NewEncoder(w).Encode(sample)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	camera, err := u.EnableRTSP(id, 1)
+	require.NoError(t, err)
+
+	var body map[string][]map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(<-bodies, &body))
+	require.Len(t, body["channels"], 2, "every channel must be sent.")
+	a.Equal("kQ7pWDEaPtkxIwV9", body["channels"][0]["rtspAlias"])
+	a.Nil(body["channels"][1]["rtspAlias"])
+	a.Equal(true, body["channels"][1]["isRtspEnabled"])
+	a.EqualValues(768, body["channels"][1]["height"], "channel settings must be kept.")
+	a.Contains(body["channels"][1], "minClientAdaptiveBitRate")
+	a.Nil(body["channels"][1]["minClientAdaptiveBitRate"], "null values must be sent back as null.")
+	a.Equal("kept", body["channels"][1]["futureSetting"], "unknown fields must be sent back.")
+	a.True(camera.Channels[1].IsRtspEnabled)
+	a.Equal("Zr3bLq0sM1Yc9TfA", camera.Channels[1].RtspAlias)
+
+	urls := (&NVR{Host: "192.168.1.1"}).StreamURLs(camera, "")
+	require.Len(t, urls, 2)
+	a.Equal("rtsps://192.168.1.1:7441/kQ7pWDEaPtkxIwV9", urls[0].RTSPS.String())
+	a.Equal("rtsp://192.168.1.1:7447/Zr3bLq0sM1Yc9TfA", urls[1].RTSP.String())
+	a.Equal("Medium", urls[1].Name)
+	a.Equal(1024, urls[1].Width)
+
+	ignore = true
+	_, err = u.DisableRTSP(id, 0)
+	<-bodies
+	a.ErrorIs(err, ErrCameraNotUpdated)
+
+	_, err = u.EnableRTSP(id, 5)
+	a.ErrorIs(err, ErrNoChannel)
+}
+
+func TestStreamURLs(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var camera Camera
+
+	require.NoError(t, json.Unmarshal(cameraSample, &camera))
+
+	u, _ := newProtectServer(t, nil)
+
+	urls, err := u.StreamURLs(&camera)
+	require.NoError(t, err)
+	require.Len(t, urls, 1, "only channels with RTSP enabled have URLs.")
+	a.Equal(0, urls[0].ChannelID)
+	a.Equal("rtsps://127.0.0.1:7441/kQ7pWDEaPtkxIwV9", urls[0].RTSPS.String(), "the host must be the one in Config.URL.")
+	a.Equal("rtsp://127.0.0.1:7447/kQ7pWDEaPtkxIwV9", urls[0].RTSP.String())
+
+	urls = (&NVR{Host: "192.168.1.1"}).StreamURLs(&camera, "fd00::1")
+	a.Equal("rtsps://[fd00::1]:7441/kQ7pWDEaPtkxIwV9", urls[0].RTSPS.String(), "default ports must be used when missing.")
+}
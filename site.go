@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -9,11 +10,16 @@ var ErrDPIDataBug = fmt.Errorf("dpi data table contains more than 1 item; please
 
 // GetSites returns a list of configured sites on the UniFi controller.
 func (u *Unifi) GetSites() ([]*Site, error) {
+	return u.GetSitesCtx(context.Background())
+}
+
+// GetSitesCtx is the same as GetSites, but uses the provided context.
+func (u *Unifi) GetSitesCtx(ctx context.Context) ([]*Site, error) {
 	var response struct {
 		Data []*Site `json:"data"`
 	}
 
-	if err := u.GetData(APISiteList, &response); err != nil {
+	if err := u.GetDataCtx(ctx, APISiteList, &response); err != nil {
 		return nil, err
 	}
 
@@ -38,34 +44,38 @@ func (u *Unifi) GetSites() ([]*Site, error) {
 
 // GetSiteDPI garners dpi data for sites.
 func (u *Unifi) GetSiteDPI(sites []*Site) ([]*DPITable, error) {
-	data := []*DPITable{}
+	return u.GetSiteDPICtx(context.Background(), sites)
+}
 
-	for _, site := range sites {
-		u.DebugLog("Polling Controller, retreiving Site DPI data, site %s", site.SiteName)
+// GetSiteDPICtx is the same as GetSiteDPI, but uses the provided context.
+func (u *Unifi) GetSiteDPICtx(ctx context.Context, sites []*Site) ([]*DPITable, error) {
+	return eachSite(ctx, u, sites, u.getSiteDPISite)
+}
 
-		var response struct {
-			Data []*DPITable `json:"data"`
-		}
+func (u *Unifi) getSiteDPISite(ctx context.Context, site *Site) ([]*DPITable, error) {
+	u.DebugLog("Polling Controller, retreiving Site DPI data, site %s", site.SiteName)
 
-		siteDPIpath := fmt.Sprintf(APISiteDPI, site.Name)
-		if err := u.GetData(siteDPIpath, &response, `{"type":"by_app"}`); err != nil {
-			return nil, err
-		}
+	var response struct {
+		Data []*DPITable `json:"data"`
+	}
 
-		if l := len(response.Data); l > 1 {
-			return nil, ErrDPIDataBug
-		} else if l == 0 {
-			u.DebugLog("Site DPI data missing! Is DPI enabled in UniFi controller? Site %s", site.SiteName)
+	siteDPIpath := fmt.Sprintf(APISiteDPI, site.Name)
+	if err := u.GetDataCtx(ctx, siteDPIpath, &response, `{"type":"by_app"}`); err != nil {
+		return nil, err
+	}
 
-			continue
-		}
+	if l := len(response.Data); l > 1 {
+		return nil, ErrDPIDataBug
+	} else if l == 0 {
+		u.DebugLog("Site DPI data missing! Is DPI enabled in UniFi controller? Site %s", site.SiteName)
 
-		response.Data[0].SourceName = site.SourceName
-		response.Data[0].SiteName = site.SiteName
-		data = append(data, response.Data[0])
+		return nil, nil
 	}
 
-	return data, nil
+	response.Data[0].SourceName = site.SourceName
+	response.Data[0].SiteName = site.SiteName
+
+	return response.Data, nil
 }
 
 // Site represents a site's data.
@@ -0,0 +1,240 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoController is returned by Site methods when the site did not come from GetSites.
+var ErrNoController = fmt.Errorf("site has no controller; get sites from GetSites")
+
+// These are the message types pushed on a site's event stream that SiteMessage decodes.
+// Other message types are delivered with only Data set.
+const (
+	SiteMessageEvents       = "events"
+	SiteMessageAlarm        = "alarm"
+	SiteMessageDeviceSync   = "device:sync"
+	SiteMessageDeviceUpdate = "device:update"
+	SiteMessageClientSync   = "sta:sync"
+	SiteMessageUserSync     = "user:sync"
+)
+
+// SiteMessage is a message pushed on a site's event stream.
+// Events, Alarms, Devices or Clients is set depending on the Type.
+type SiteMessage struct {
+	Site    *Site
+	Type    string // meta.message: events, alarm, device:sync, sta:sync, etc.
+	Events  []*Event
+	Alarms  []*Alarm
+	Devices *Devices
+	Clients []*Client
+	Data    []json.RawMessage
+	// Cursor is the time of the newest event seen. Pass it to SubscribeSince to
+	// resume a subscription without missing or repeating events.
+	Cursor time.Time
+}
+
+// siteStream holds the last-seen cursor for a site subscription. Events at or before
+// the cursor are not delivered again; seen holds the IDs of the events at the cursor.
+type siteStream struct {
+	*Site
+	cursor time.Time
+	seen   map[string]struct{}
+}
+
+// newEvents returns the events newer than the cursor, oldest first, and moves the cursor.
+// The events are sorted first; the controller does not always send them in order.
+func (s *siteStream) newEvents(events []*Event) []*Event {
+	for _, event := range events {
+		if event.Datetime.IsZero() {
+			event.Datetime = time.UnixMilli(event.Time)
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b *Event) int { return a.Datetime.Compare(b.Datetime) })
+
+	fresh := []*Event{}
+
+	for _, event := range events {
+		if event.Datetime.Before(s.cursor) {
+			continue
+		}
+
+		// A cursor from SubscribeSince has no seen IDs; its events were already delivered.
+		if len(s.seen) == 0 && !s.cursor.IsZero() && event.Datetime.Equal(s.cursor) {
+			continue
+		}
+
+		if _, ok := s.seen[event.ID]; ok {
+			continue
+		}
+
+		if event.Datetime.After(s.cursor) {
+			s.cursor = event.Datetime
+			s.seen = make(map[string]struct{})
+		}
+
+		s.seen[event.ID] = struct{}{}
+		fresh = append(fresh, event)
+	}
+
+	return fresh
+}
+
+// decode parses a websocket message. It returns nil if the message has nothing new.
+func (s *siteStream) decode(data []byte) (*SiteMessage, error) {
+	var response struct {
+		Meta struct {
+			Message string `json:"message"`
+		} `json:"meta"`
+		Data []json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("decoding site message: %w", err)
+	}
+
+	msg := &SiteMessage{Site: s.Site, Type: response.Meta.Message, Data: response.Data}
+	u := s.controller
+
+	switch msg.Type {
+	case SiteMessageEvents:
+		if err := unmarshalItems(response.Data, &msg.Events); err != nil {
+			return nil, err
+		}
+
+		for _, event := range msg.Events {
+			event.SourceName = u.URL
+			event.SiteName = s.SiteName
+		}
+
+		if msg.Events = s.newEvents(msg.Events); len(msg.Events) == 0 {
+			return nil, nil
+		}
+	case SiteMessageAlarm:
+		if err := unmarshalItems(response.Data, &msg.Alarms); err != nil {
+			return nil, err
+		}
+
+		for _, alarm := range msg.Alarms {
+			alarm.SourceName = u.URL
+			alarm.SiteName = s.SiteName
+		}
+	case SiteMessageDeviceSync, SiteMessageDeviceUpdate:
+		msg.Devices = u.parseDevices(response.Data, s.Site)
+	case SiteMessageClientSync, SiteMessageUserSync:
+		if err := unmarshalItems(response.Data, &msg.Clients); err != nil {
+			return nil, err
+		}
+
+		for _, client := range msg.Clients {
+			client.SourceName = u.URL
+			client.SiteName = s.SiteName
+			client.Hostname = strings.TrimSpace(pick(client.Hostname, client.Name, client.Mac))
+			client.Name = strings.TrimSpace(pick(client.Name, client.Hostname))
+		}
+	}
+
+	msg.Cursor = s.cursor
+
+	return msg, nil
+}
+
+// backfill returns the events that happened since the cursor, while the stream was disconnected.
+func (s *siteStream) backfill(ctx context.Context) (*SiteMessage, error) {
+	if s.cursor.IsZero() {
+		return nil, nil
+	}
+
+	// GetSiteEvents rounds to the hour; add one so the cursor is always in the window.
+	events, err := s.controller.GetSiteEventsCtx(ctx, s.Site, time.Since(s.cursor)+time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	if events = s.newEvents(events); len(events) == 0 {
+		return nil, nil
+	}
+
+	return &SiteMessage{Site: s.Site, Type: SiteMessageEvents, Events: events, Cursor: s.cursor}, nil
+}
+
+func unmarshalItems[T any](data []json.RawMessage, items *[]*T) error {
+	for _, raw := range data {
+		item := new(T)
+		if err := json.Unmarshal(raw, item); err != nil {
+			return fmt.Errorf("decoding site message: %w", err)
+		}
+
+		*items = append(*items, item)
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel of the events, alarms and device and client updates
+// pushed by the controller for this site. See SubscribeSince.
+func (s *Site) Subscribe(ctx context.Context) (<-chan *SiteMessage, error) {
+	return s.SubscribeSince(ctx, time.Time{})
+}
+
+// SubscribeSince returns a channel of the events, alarms and device and client updates
+// pushed by the controller for this site. Events after the cursor are requested first,
+// and when the websocket reconnects, the events missed while it was disconnected are
+// requested. Events are never delivered twice, and events at the cursor are not delivered,
+// so a SiteMessage.Cursor resumes where it left off. Use a zero cursor to start with new events.
+// The channel is closed when the context is done. The site must come from GetSites.
+func (s *Site) SubscribeSince(ctx context.Context, cursor time.Time) (<-chan *SiteMessage, error) {
+	if s == nil || s.Name == "" {
+		return nil, ErrNoSiteProvided
+	}
+
+	if s.controller == nil {
+		return nil, ErrNoController
+	}
+
+	u := s.controller
+	if _, err := u.websocketTransport(); err != nil {
+		return nil, err
+	}
+
+	stream := &siteStream{Site: s, cursor: cursor, seen: make(map[string]struct{})}
+	messages := make(chan *SiteMessage, 100) //nolint:gomnd
+	ctx = withSite(ctx, s)
+
+	send := func(msg *SiteMessage) {
+		select {
+		case messages <- msg:
+		case <-ctx.Done():
+		}
+	}
+
+	connect := func(ctx context.Context) (string, error) {
+		msg, err := stream.backfill(ctx)
+		if msg != nil {
+			send(msg)
+		}
+
+		return fmt.Sprintf(APISiteEventsPath, s.Name), err
+	}
+
+	handle := func(data []byte) {
+		msg, err := stream.decode(data)
+		if err != nil {
+			u.logger().Error("decoding site message", "site", s.SiteName, "error", err)
+		} else if msg != nil {
+			send(msg)
+		}
+	}
+
+	go func() {
+		defer close(messages)
+		u.keepWebsocket(ctx, connect, handle)
+	}()
+
+	return messages, nil
+}
@@ -0,0 +1,127 @@
+package unifi // nolint: testpackage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// siteEvent returns a site event, as JSON, that happened secs after 1700000000000 ms.
+func siteEvent(id string, secs int64) string {
+	when := time.UnixMilli(1700000000000).Add(time.Duration(secs) * time.Second)
+
+	return fmt.Sprintf(`{"_id":%q,"key":"EVT_WU_Connected","subsystem":"wlan","msg":"connected %s",`+
+		`"datetime":%q,"time":%d}`, id, id, when.UTC().Format(time.RFC3339), when.UnixMilli())
+}
+
+func TestSiteSubscribe(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	cursor := time.UnixMilli(1700000000000)
+	connections := make(chan struct{}, 2)
+	reconnected := atomic.Bool{}
+
+	ws := websocket.Handler(func(conn *websocket.Conn) {
+		connections <- struct{}{}
+
+		if reconnected.Load() {
+			<-conn.Request().Context().Done()
+			return
+		}
+
+		for _, msg := range []string{
+			// out of order, and e3 was already delivered.
+			`{"meta":{"rc":"ok","message":"events"},"data":[` + siteEvent("e4", 180) + `,` + siteEvent("e3", 120) + `,` +
+				siteEvent("e3b", 150) + `]}`,
+			`{"meta":{"rc":"ok","message":"events"},"data":[` + siteEvent("e4", 180) + `]}`, // duplicate, not delivered.
+			`{"meta":{"rc":"ok","message":"device:sync"},"data":[{"type":"uap","model":"U7PG2","name":"ap1","mac":"aa"}]}`,
+			`{"meta":{"rc":"ok","message":"sta:sync"},"data":[{"mac":"bb","hostname":"laptop","rx_bytes":"5"}]}`,
+			`{"meta":{"rc":"ok","message":"alarm"},"data":[{"_id":"a1","key":"EVT_IPS_Alert","msg":"alert"}]}`,
+			`{"meta":{"rc":"ok","message":"speed-test:update"},"data":[{"xput_download":100}]}`,
+		} {
+			if err := websocket.Message.Send(conn, msg); err != nil {
+				t.Error(err)
+			}
+		}
+
+		reconnected.Store(true)
+	})
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefixNew + fmt.Sprintf(APISiteEventsPath, "default"):
+			ws.ServeHTTP(w, r)
+		case APIPrefixNew + fmt.Sprintf(APIEventPath, "default"):
+			events := siteEvent("e1", -60) + "," + siteEvent("e0", 0) + "," + siteEvent("e2", 60) + "," + siteEvent("e3", 120)
+			if reconnected.Load() {
+				events += "," + siteEvent("e5", 240)
+			}
+
+			_, _ = w.Write([]byte(`{"meta":{"rc":"ok"},"data":[` + events + `]}`))
+		}
+	})
+	u.Retry = &RetryPolicy{MinBackoff: time.Millisecond}
+	site := &Site{Name: "default", SiteName: "Default (default)", controller: u}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, err := site.SubscribeSince(ctx, cursor)
+	require.NoError(t, err)
+
+	msg := <-messages
+	a.Equal(SiteMessageEvents, msg.Type)
+	require.Len(t, msg.Events, 2, "events at or before the cursor must not be delivered.")
+	a.Equal("e2", msg.Events[0].ID)
+	a.Equal("e3", msg.Events[1].ID)
+	a.Equal("Default (default)", msg.Events[0].SiteName)
+
+	msg = <-messages
+	require.Len(t, msg.Events, 2, "events delivered by the backfill must not be delivered again.")
+	a.Equal("e3b", msg.Events[0].ID, "events must be sorted before the cursor moves.")
+	a.Equal("e4", msg.Events[1].ID)
+	a.True(cursor.Add(3 * time.Minute).Equal(msg.Cursor))
+
+	msg = <-messages
+	a.Equal(SiteMessageDeviceSync, msg.Type)
+	require.NotNil(t, msg.Devices)
+	require.Len(t, msg.Devices.UAPs, 1)
+	a.Equal("ap1", msg.Devices.UAPs[0].Name)
+
+	msg = <-messages
+	a.Equal(SiteMessageClientSync, msg.Type)
+	require.Len(t, msg.Clients, 1)
+	a.Equal("laptop", msg.Clients[0].Name)
+	a.EqualValues(5, msg.Clients[0].RxBytes.Val)
+
+	msg = <-messages
+	a.Equal(SiteMessageAlarm, msg.Type)
+	require.Len(t, msg.Alarms, 1)
+	a.Equal("alert", msg.Alarms[0].Msg)
+
+	msg = <-messages
+	a.Equal("speed-test:update", msg.Type)
+	a.Len(msg.Data, 1)
+
+	msg = <-messages
+	require.Len(t, msg.Events, 1, "a reconnect must request the events missed while disconnected.")
+	a.Equal("e5", msg.Events[0].ID)
+
+	<-connections
+	<-connections
+	cancel()
+
+	for range messages { //nolint:revive
+		// the channel must be closed when the context is done.
+	}
+
+	_, err = (&Site{Name: "default"}).Subscribe(ctx)
+	a.ErrorIs(err, ErrNoController)
+}
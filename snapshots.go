@@ -0,0 +1,176 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCameraOffline may be matched with errors.Is against the error returned
+// when an image is requested from a camera that is not connected to the NVR.
+var ErrCameraOffline = fmt.Errorf("camera is offline")
+
+// CameraOfflineError is returned by GetCameraSnapshot when the NVR cannot get
+// a snapshot because the camera is not connected. Use errors.As to get one.
+type CameraOfflineError struct {
+	CameraID string
+	Name     string
+	LastSeen time.Time
+	// Err is the error returned by the snapshot request.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (e *CameraOfflineError) Error() string {
+	return fmt.Sprintf("%s (%s), last seen %v: %v", ErrCameraOffline, e.Name, e.LastSeen.Round(time.Second), e.Err)
+}
+
+// Unwrap returns the error from the snapshot request.
+func (e *CameraOfflineError) Unwrap() error {
+	return e.Err
+}
+
+// Is allows matching a CameraOfflineError to ErrCameraOffline with errors.Is.
+func (e *CameraOfflineError) Is(target error) bool {
+	return target == ErrCameraOffline //nolint:errorlint,goerr113
+}
+
+// GetCameraSnapshot returns a current JPEG image from a camera. Width and height are
+// optional; pass 0 to get the camera's native resolution. highQuality requests the
+// image from the camera's high quality channel. If the camera is offline the error
+// is a *CameraOfflineError.
+func (u *Unifi) GetCameraSnapshot(cameraID string, width, height int, highQuality bool) ([]byte, error) {
+	return u.GetCameraSnapshotCtx(context.Background(), cameraID, width, height, highQuality)
+}
+
+// GetCameraSnapshotCtx is the same as GetCameraSnapshot, but uses the provided context.
+func (u *Unifi) GetCameraSnapshotCtx(
+	ctx context.Context, cameraID string, width, height int, highQuality bool,
+) ([]byte, error) {
+	params := url.Values{}
+	params.Set("ts", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	params.Set("force", "true")
+	params.Set("highQuality", strconv.FormatBool(highQuality))
+
+	if width > 0 {
+		params.Set("w", strconv.Itoa(width))
+	}
+
+	if height > 0 {
+		params.Set("h", strconv.Itoa(height))
+	}
+
+	body, err := u.GetRawCtx(ctx, fmt.Sprintf(APIProtectCameraSnapshotPath, cameraID)+"?"+params.Encode())
+	if err != nil {
+		return nil, u.cameraError(ctx, cameraID, err)
+	}
+
+	return body, nil
+}
+
+// WriteCameraSnapshot writes a current JPEG image from a camera to w. See GetCameraSnapshot.
+func (u *Unifi) WriteCameraSnapshot(w io.Writer, cameraID string, width, height int, highQuality bool) error {
+	return u.WriteCameraSnapshotCtx(context.Background(), w, cameraID, width, height, highQuality)
+}
+
+// WriteCameraSnapshotCtx is the same as WriteCameraSnapshot, but uses the provided context.
+func (u *Unifi) WriteCameraSnapshotCtx(
+	ctx context.Context, w io.Writer, cameraID string, width, height int, highQuality bool,
+) error {
+	body, err := u.GetCameraSnapshotCtx(ctx, cameraID, width, height, highQuality)
+	if err != nil {
+		return err
+	}
+
+	return writeImage(w, body)
+}
+
+// GetEventThumbnail returns the JPEG thumbnail for a Protect event. The ID may be the
+// event's ID or the Thumbnail ID from a ProtectEvent.
+func (u *Unifi) GetEventThumbnail(eventID string) ([]byte, error) {
+	return u.GetEventThumbnailCtx(context.Background(), eventID)
+}
+
+// GetEventThumbnailCtx is the same as GetEventThumbnail, but uses the provided context.
+func (u *Unifi) GetEventThumbnailCtx(ctx context.Context, eventID string) ([]byte, error) {
+	return u.GetRawCtx(ctx, fmt.Sprintf(APIProtectEventThumbnailPath, eventImageID(eventID)))
+}
+
+// WriteEventThumbnail writes the JPEG thumbnail for a Protect event to w. See GetEventThumbnail.
+func (u *Unifi) WriteEventThumbnail(w io.Writer, eventID string) error {
+	return u.WriteEventThumbnailCtx(context.Background(), w, eventID)
+}
+
+// WriteEventThumbnailCtx is the same as WriteEventThumbnail, but uses the provided context.
+func (u *Unifi) WriteEventThumbnailCtx(ctx context.Context, w io.Writer, eventID string) error {
+	body, err := u.GetEventThumbnailCtx(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	return writeImage(w, body)
+}
+
+// GetEventHeatmap returns the PNG motion heatmap for a Protect event. The ID may be the
+// event's ID or the Heatmap ID from a ProtectEvent.
+func (u *Unifi) GetEventHeatmap(eventID string) ([]byte, error) {
+	return u.GetEventHeatmapCtx(context.Background(), eventID)
+}
+
+// GetEventHeatmapCtx is the same as GetEventHeatmap, but uses the provided context.
+func (u *Unifi) GetEventHeatmapCtx(ctx context.Context, eventID string) ([]byte, error) {
+	return u.GetRawCtx(ctx, fmt.Sprintf(APIProtectEventHeatmapPath, eventImageID(eventID)))
+}
+
+// WriteEventHeatmap writes the PNG motion heatmap for a Protect event to w. See GetEventHeatmap.
+func (u *Unifi) WriteEventHeatmap(w io.Writer, eventID string) error {
+	return u.WriteEventHeatmapCtx(context.Background(), w, eventID)
+}
+
+// WriteEventHeatmapCtx is the same as WriteEventHeatmap, but uses the provided context.
+func (u *Unifi) WriteEventHeatmapCtx(ctx context.Context, w io.Writer, eventID string) error {
+	body, err := u.GetEventHeatmapCtx(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	return writeImage(w, body)
+}
+
+// cameraError checks if a camera is offline after a failed request. If it is,
+// the error is wrapped in a CameraOfflineError, otherwise it is returned as-is.
+func (u *Unifi) cameraError(ctx context.Context, cameraID string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	camera, camErr := u.GetCameraByIDCtx(ctx, cameraID)
+	if camErr != nil || camera.IsConnected {
+		return err
+	}
+
+	return &CameraOfflineError{
+		CameraID: cameraID,
+		Name:     camera.Name,
+		LastSeen: camera.LastSeen.Val,
+		Err:      err,
+	}
+}
+
+// eventImageID removes the e- prefix from thumbnail and heatmap IDs; the API wants the event ID.
+func eventImageID(id string) string {
+	return strings.TrimPrefix(id, "e-")
+}
+
+func writeImage(w io.Writer, body []byte) error {
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("writing image: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package unifi // nolint: testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCameraSnapshot(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var offline map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(cameraSample, &offline))
+	offline["isConnected"] = false
+	offline["state"] = "DISCONNECTED"
+
+	queries := make(chan string, 1)
+	jpeg := []byte("\xff\xd8\xff\xe0 front door")
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefixNew + "/api/cameras":
+			_ = json.NewEncoder(w).Encode([]interface{}{offline})
+		case APIPrefixNew + "/api/cameras/6183e5f40271f603e7000440/snapshot":
+			queries <- r.URL.RawQuery
+			_, _ = w.Write(jpeg)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	image, err := u.GetCameraSnapshot("6183e5f40271f603e7000440", 640, 0, true)
+	require.NoError(t, err)
+	a.Equal(jpeg, image)
+
+	query := <-queries
+	a.Contains(query, "w=640")
+	a.NotContains(query, "h=")
+	a.Contains(query, "highQuality=true")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, u.WriteCameraSnapshot(&buf, "6183e5f40271f603e7000440", 0, 0, false))
+	a.Equal(jpeg, buf.Bytes())
+	a.Contains(<-queries, "highQuality=false")
+
+	_, err = u.GetCameraSnapshot("65a1c2d30188f903e7001a22", 0, 0, false)
+
+	var offlineErr *CameraOfflineError
+
+	require.ErrorAs(t, err, &offlineErr)
+	a.ErrorIs(err, ErrCameraOffline)
+	a.ErrorIs(err, ErrInvalidStatusCode, "the request error must be wrapped.")
+	a.Equal("Driveway PTZ", offlineErr.Name)
+	a.Equal(time.UnixMilli(1699999999000), offlineErr.LastSeen)
+
+	_, err = u.GetCameraSnapshot("unknown", 0, 0, false)
+	a.ErrorIs(err, ErrInvalidStatusCode)
+	a.False(errors.Is(err, ErrCameraOffline), "an unknown camera is not offline.")
+}
+
+func TestEventImages(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	u, _ := newProtectServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+
+	thumbnail, err := u.GetEventThumbnail("e-6557a20b00e80703e7001b4a")
+	require.NoError(t, err)
+	a.Equal(APIPrefixNew+"/api/events/6557a20b00e80703e7001b4a/thumbnail", string(thumbnail))
+
+	var buf bytes.Buffer
+
+	require.NoError(t, u.WriteEventHeatmap(&buf, "6557a20b00e80703e7001b4a"))
+	a.Equal(APIPrefixNew+"/api/events/6557a20b00e80703e7001b4a/heatmap", buf.String())
+}
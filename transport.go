@@ -0,0 +1,108 @@
+package unifi
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// Middleware wraps an http.RoundTripper with another. Provide a list of these
+// in Config.Middleware to add tracing, request logging, custom headers, etc.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as
+// an http.RoundTripper. Useful when writing a Middleware.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTransport builds the transport chain for a controller. From the inside out:
+// the base transport with the TLS settings applied, then the Config.Middleware,
+// then authTransport which adds the API key or CSRF headers to every request.
+// The chain without authTransport is also returned, for unauthenticated requests.
+func (u *Unifi) newTransport() (http.RoundTripper, http.RoundTripper) {
+	transport := u.baseTransport()
+
+	for i := len(u.Middleware) - 1; i >= 0; i-- {
+		transport = u.Middleware[i](transport)
+	}
+
+	return &authTransport{Unifi: u, next: transport}, transport
+}
+
+// baseTransport returns Config.RoundTripper, or a new http.Transport, with the
+// VerifySSL and SSLCert settings applied. An *http.Transport is cloned so the
+// caller's transport is not modified. Any other RoundTripper is responsible for
+// its own TLS settings. Certificate pinning needs the TLS handshake, so with SSLCert
+// any other RoundTripper fails every request; NewUnifi rejects that combination.
+func (u *Unifi) baseTransport() http.RoundTripper {
+	var transport *http.Transport
+
+	switch base := u.RoundTripper.(type) {
+	case nil:
+		transport = &http.Transport{}
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		if len(u.SSLCert) > 0 {
+			return RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return nil, ErrPinningUnsupported
+			})
+		}
+
+		return base
+	}
+
+	transport.TLSClientConfig = u.tlsConfig(transport.TLSClientConfig)
+
+	return transport
+}
+
+// tlsConfig returns a copy of a TLS config, or a new one, with the VerifySSL and SSLCert settings applied.
+func (u *Unifi) tlsConfig(base *tls.Config) *tls.Config {
+	config := &tls.Config{} // nolint: gosec
+	if base != nil {
+		config = base.Clone()
+	}
+
+	config.InsecureSkipVerify = !u.VerifySSL // nolint: gosec
+
+	if len(u.SSLCert) > 0 {
+		config.InsecureSkipVerify = true // nolint: gosec
+		config.VerifyPeerCertificate = u.verifyPeerCertificate
+	}
+
+	return config
+}
+
+// authTransport adds the API key, or the saved CSRF token, to every request,
+// and saves the CSRF token returned with every response.
+type authTransport struct {
+	*Unifi
+	next http.RoundTripper
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context()) // RoundTrippers must not modify the request.
+
+	if a.APIKey != "" {
+		// API keys need no cookie or CSRF token.
+		req.Header.Set(APIKeyHeader, a.APIKey)
+	} else if csrf := a.getCSRF(); csrf != "" {
+		// Add the saved CSRF header.
+		req.Header.Set("X-CSRF-Token", csrf)
+	}
+
+	resp, err := a.next.RoundTrip(req)
+	if err != nil {
+		return resp, err //nolint:wrapcheck
+	}
+
+	// Save the returned CSRF header.
+	a.saveCSRF(resp.Header)
+
+	return resp, nil
+}
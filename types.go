@@ -2,13 +2,18 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
@@ -157,6 +162,60 @@ const (
 	APIEventPathAlarms string = "/api/s/%s/list/alarm"
 	// APIPrefixNew is the prefix added to the new API paths; except login. duh.
 	APIPrefixNew string = "/proxy/protect"
+	// APIProtectBootstrapPath returns the complete state of a Protect NVR.
+	APIProtectBootstrapPath string = "/api/bootstrap"
+	// APIProtectNVRPath returns the Protect NVR, with its storage and hardware health.
+	APIProtectNVRPath string = "/api/nvr"
+	// APIProtectEventsPath returns Protect motion, smart detection, ring and sensor events.
+	APIProtectEventsPath string = "/api/events"
+	// APIProtectCameraPath is a single Protect camera; PATCH it to change settings.
+	APIProtectCameraPath string = "/api/cameras/%s"
+	// APIProtectCameraSnapshotPath returns a current JPEG image from a camera.
+	APIProtectCameraSnapshotPath string = "/api/cameras/%s/snapshot"
+	// APIProtectPTZMovePath moves a PTZ camera.
+	APIProtectPTZMovePath string = "/api/cameras/%s/move"
+	// APIProtectPTZPositionPath returns the pan, tilt, zoom and focus steps of a PTZ camera.
+	APIProtectPTZPositionPath string = "/api/cameras/%s/ptz/position"
+	// APIProtectPTZPresetsPath lists PTZ presets; POST to it to save the current position as a preset.
+	APIProtectPTZPresetsPath string = "/api/cameras/%s/ptz/preset"
+	// APIProtectPTZPresetPath is a single PTZ preset slot; DELETE it to remove the preset.
+	APIProtectPTZPresetPath string = "/api/cameras/%s/ptz/preset/%d"
+	// APIProtectPTZGotoPath moves a PTZ camera to a preset slot.
+	APIProtectPTZGotoPath string = "/api/cameras/%s/ptz/goto/%d"
+	// APIProtectPTZPatrolsPath lists PTZ patrols.
+	APIProtectPTZPatrolsPath string = "/api/cameras/%s/ptz/patrol"
+	// APIProtectPTZPatrolStartPath starts a PTZ patrol slot.
+	APIProtectPTZPatrolStartPath string = "/api/cameras/%s/ptz/patrol/start/%d"
+	// APIProtectPTZPatrolStopPath stops the running PTZ patrol.
+	APIProtectPTZPatrolStopPath string = "/api/cameras/%s/ptz/patrol/stop"
+	// APIProtectLightsPath returns Protect floodlights.
+	APIProtectLightsPath string = "/api/lights"
+	// APIProtectLightPath is a single Protect floodlight; PATCH it to change settings.
+	APIProtectLightPath string = "/api/lights/%s"
+	// APIProtectSensorsPath returns Protect sensors.
+	APIProtectSensorsPath string = "/api/sensors"
+	// APIProtectChimesPath returns Protect chimes.
+	APIProtectChimesPath string = "/api/chimes"
+	// APIProtectChimePlayPath rings a chime.
+	APIProtectChimePlayPath string = "/api/chimes/%s/play-speaker"
+	// APIProtectDoorlocksPath returns Protect smart locks.
+	APIProtectDoorlocksPath string = "/api/doorlocks"
+	// APIProtectDoorlockOpenPath unlocks a smart lock.
+	APIProtectDoorlockOpenPath string = "/api/doorlocks/%s/open"
+	// APIProtectDoorlockClosePath locks a smart lock.
+	APIProtectDoorlockClosePath string = "/api/doorlocks/%s/close"
+	// APIProtectEventThumbnailPath returns the JPEG thumbnail for an event.
+	APIProtectEventThumbnailPath string = "/api/events/%s/thumbnail"
+	// APIProtectEventHeatmapPath returns the PNG motion heatmap for an event.
+	APIProtectEventHeatmapPath string = "/api/events/%s/heatmap"
+	// APIProtectVideoPreparePath prepares a clip on the NVR for download.
+	APIProtectVideoPreparePath string = "/api/video/prepare"
+	// APIProtectVideoDownloadPath downloads a prepared clip.
+	APIProtectVideoDownloadPath string = "/api/video/download"
+	// APIProtectUpdatesPath is the Protect websocket that pushes changes after a lastUpdateId.
+	APIProtectUpdatesPath string = "/ws/updates"
+	// APISiteEventsPath is the websocket that pushes a site's events, alarms and device and client updates.
+	APISiteEventsPath string = "/wss/s/%s/events"
 	// APIAnomaliesPath returns site anomalies.
 	APIAnomaliesPath string = "/api/s/%s/stat/anomalies"
 	APICommandPath   string = "/api/s/%s/cmd"
@@ -205,12 +264,44 @@ type Devices struct {
 type Config struct {
 	User      string
 	Pass      string
+	APIKey    string // UniFi OS API key. When set, User and Pass are not used and Login is skipped.
 	URL       string
 	SSLCert   [][]byte
 	ErrorLog  Logger
 	DebugLog  Logger
 	Timeout   time.Duration // how long to wait for replies, default: forever.
 	VerifySSL bool
+	// TOTPSecret is the base32 secret of an account with 2FA enabled.
+	// Login uses it to compute a token when the controller asks for one.
+	TOTPSecret string
+	// MFAToken is called to get a 2FA token when the controller asks for one.
+	// It takes precedence over TOTPSecret.
+	MFAToken func() (string, error)
+	// Retry configures retries with backoff for transient failures. nil disables retries.
+	Retry *RetryPolicy
+	// RoundTripper is the base transport used for every request. Default: a new http.Transport.
+	// An *http.Transport is cloned and VerifySSL and SSLCert are applied to the clone.
+	// SSLCert requires an *http.Transport, so the certificate is checked before anything
+	// is sent; NewUnifi returns ErrPinningUnsupported for any other RoundTripper.
+	// Websockets are dialed with the transport's Proxy and DialContext, so the
+	// subscriptions return ErrWebsocketTransport for any other RoundTripper.
+	RoundTripper http.RoundTripper
+	// Middleware wraps RoundTripper; the first Middleware in the list is the outermost.
+	// The API key, CSRF token and cookie handling are layered on top of these.
+	// Middleware is not used for websocket subscriptions.
+	Middleware []Middleware
+	// Concurrency is how many sites are polled at once by the methods that
+	// accept a list of sites. Default: 1, one site at a time.
+	Concurrency int
+	// ReAuth makes the library log in again, and replay the request once,
+	// when the controller answers 401 or 403 because the session expired.
+	ReAuth bool
+	// Slog receives structured log lines with attributes like site, path, status and elapsed.
+	// ErrorLog and DebugLog write to Slog when they are not provided. When Slog is not
+	// provided, structured lines are formatted as key=value pairs for ErrorLog and DebugLog.
+	Slog *slog.Logger
+	// Observer is called after every request with the API path, status, size and duration.
+	Observer RequestObserver
 }
 
 type UnifiClient interface { //nolint: revive
@@ -273,6 +364,144 @@ type UnifiClient interface { //nolint: revive
 	GetUsers(sites []*Site, hours int) ([]*User, error)
 }
 
+// UnifiClientCtx is the context-aware equivalent of UnifiClient. Every method
+// accepts a context that may be used to cancel an in-flight request or to
+// carry a request-scoped deadline. Config.Timeout still applies to each request.
+type UnifiClientCtx interface { //nolint: revive
+	// GetAlarmsCtx returns Alarms for a list of Sites.
+	GetAlarmsCtx(ctx context.Context, sites []*Site) ([]*Alarm, error)
+	// GetAlarmsSiteCtx retreives the Alarms for a single Site.
+	GetAlarmsSiteCtx(ctx context.Context, site *Site) ([]*Alarm, error)
+	// GetAnomaliesCtx returns Anomalies for a list of Sites.
+	GetAnomaliesCtx(ctx context.Context, sites []*Site, timeRange ...time.Time) ([]*Anomaly, error)
+	// GetAnomaliesSiteCtx retreives the Anomalies for a single Site.
+	GetAnomaliesSiteCtx(ctx context.Context, site *Site, timeRange ...time.Time) ([]*Anomaly, error)
+	// GetClientsCtx returns a response full of clients' data from the UniFi Controller.
+	GetClientsCtx(ctx context.Context, sites []*Site) ([]*Client, error)
+	// GetClientsDPICtx garners dpi data for clients.
+	GetClientsDPICtx(ctx context.Context, sites []*Site) ([]*DPITable, error)
+	// GetDevicesCtx returns a response full of devices' data from the UniFi Controller.
+	GetDevicesCtx(ctx context.Context, sites []*Site) (*Devices, error)
+	// GetUSWsCtx returns all switches, an error, or nil if there are no switches.
+	GetUSWsCtx(ctx context.Context, site *Site) ([]*USW, error)
+	// GetUAPsCtx returns all access points, an error, or nil if there are no APs.
+	GetUAPsCtx(ctx context.Context, site *Site) ([]*UAP, error)
+	// GetUDMsCtx returns all dream machines, an error, or nil if there are no UDMs.
+	GetUDMsCtx(ctx context.Context, site *Site) ([]*UDM, error)
+	// GetUXGsCtx returns all 10Gb gateways, an error, or nil if there are no UXGs.
+	GetUXGsCtx(ctx context.Context, site *Site) ([]*UXG, error)
+	// GetUSGsCtx returns all 1Gb gateways, an error, or nil if there are no USGs.
+	GetUSGsCtx(ctx context.Context, site *Site) ([]*USG, error)
+	// GetEventsCtx returns a response full of UniFi Events for the last 1 hour from multiple sites.
+	GetEventsCtx(ctx context.Context, sites []*Site, hours time.Duration) ([]*Event, error)
+	// GetSiteEventsCtx retrieves the last 1 hour's worth of events from a single site.
+	GetSiteEventsCtx(ctx context.Context, site *Site, hours time.Duration) ([]*Event, error)
+	// GetIDSCtx returns Intrusion Detection Systems events for a list of Sites.
+	GetIDSCtx(ctx context.Context, sites []*Site, timeRange ...time.Time) ([]*IDS, error)
+	// GetIDSSiteCtx retrieves the Intrusion Detection System Data for a single Site.
+	GetIDSSiteCtx(ctx context.Context, site *Site, timeRange ...time.Time) ([]*IDS, error)
+	// GetNetworksCtx returns a response full of network data from the UniFi Controller.
+	GetNetworksCtx(ctx context.Context, sites []*Site) ([]Network, error)
+	// GetSitesCtx returns a list of configured sites on the UniFi controller.
+	GetSitesCtx(ctx context.Context) ([]*Site, error)
+	// GetSiteDPICtx garners dpi data for sites.
+	GetSiteDPICtx(ctx context.Context, sites []*Site) ([]*DPITable, error)
+	// GetRogueAPsCtx returns RogueAPs for a list of Sites.
+	GetRogueAPsCtx(ctx context.Context, sites []*Site) ([]*RogueAP, error)
+	// GetRogueAPsSiteCtx returns RogueAPs for a single Site.
+	GetRogueAPsSiteCtx(ctx context.Context, site *Site) ([]*RogueAP, error)
+	// LoginCtx can be called to grab a new authentication cookie.
+	LoginCtx(ctx context.Context) error
+	// LogoutCtx closes the current session.
+	LogoutCtx(ctx context.Context) error
+	// GetServerDataCtx sets the controller's version and UUID.
+	GetServerDataCtx(ctx context.Context) (*ServerStatus, error)
+	// GetUsersCtx returns a response full of clients that connected to the UDM within the provided amount of time.
+	GetUsersCtx(ctx context.Context, sites []*Site, hours int) ([]*User, error)
+	// GetCamerasCtx returns all of the Protect cameras known to the NVR.
+	GetCamerasCtx(ctx context.Context) ([]*Camera, error)
+	// GetCameraByIDCtx returns the camera with the provided ID.
+	GetCameraByIDCtx(ctx context.Context, value string) (*Camera, error)
+	// GetCameraByNameCtx returns the camera with the provided (display) name.
+	GetCameraByNameCtx(ctx context.Context, value string) (*Camera, error)
+	// GetClipBytesCtx prepares and downloads a clip from a camera for a time window.
+	GetClipBytesCtx(ctx context.Context, cameraID string, start, end time.Time) ([]byte, error)
+	// StreamClip prepares and downloads a clip from a camera for a time window, and copies it to w.
+	StreamClip(ctx context.Context, cameraID string, start, end time.Time, w io.Writer, opts *ClipOptions) error
+	// StreamClipSegments prepares and downloads a long clip in segments, each to its own writer.
+	StreamClipSegments(
+		ctx context.Context, cameraID string, start, end time.Time, create ClipSegmentWriter, opts *ClipOptions,
+	) error
+	// SubscribeProtect returns a channel of the changes pushed by the Protect NVR.
+	SubscribeProtect(ctx context.Context) (<-chan *ProtectUpdate, error)
+	// UpdateCameraCtx changes the camera settings provided in the patch.
+	UpdateCameraCtx(ctx context.Context, cameraID string, patch *CameraPatch) (*Camera, error)
+	// GetProtectBootstrapCtx returns the complete state of the Protect NVR.
+	GetProtectBootstrapCtx(ctx context.Context) (*Bootstrap, error)
+	// GetProtectEventsCtx returns the Protect events that started within a time window.
+	GetProtectEventsCtx(ctx context.Context, start, end time.Time, filter *ProtectEventFilter) ([]*ProtectEvent, error)
+	// GetCameraSnapshotCtx returns a current JPEG image from a camera.
+	GetCameraSnapshotCtx(ctx context.Context, cameraID string, width, height int, highQuality bool) ([]byte, error)
+	// GetEventThumbnailCtx returns the JPEG thumbnail for a Protect event.
+	GetEventThumbnailCtx(ctx context.Context, eventID string) ([]byte, error)
+	// GetEventHeatmapCtx returns the PNG motion heatmap for a Protect event.
+	GetEventHeatmapCtx(ctx context.Context, eventID string) ([]byte, error)
+	// GetPTZPositionCtx returns where a PTZ camera is pointing.
+	GetPTZPositionCtx(ctx context.Context, camera *Camera) (*PTZPosition, error)
+	// GetPTZPresetsCtx returns the saved positions on a PTZ camera.
+	GetPTZPresetsCtx(ctx context.Context, camera *Camera) ([]*PTZPreset, error)
+	// GetPTZPatrolsCtx returns the preset tours on a PTZ camera.
+	GetPTZPatrolsCtx(ctx context.Context, camera *Camera) ([]*PTZPatrol, error)
+	// MovePTZCtx moves a PTZ camera from where it is pointing.
+	MovePTZCtx(ctx context.Context, camera *Camera, move PTZPosition) error
+	// MovePTZToCtx points a PTZ camera at a position.
+	MovePTZToCtx(ctx context.Context, camera *Camera, position PTZPosition) error
+	// GotoPTZPresetCtx moves a PTZ camera to a saved position.
+	GotoPTZPresetCtx(ctx context.Context, camera *Camera, slot int) error
+	// SavePTZPresetCtx saves where a PTZ camera is pointing as a new preset.
+	SavePTZPresetCtx(ctx context.Context, camera *Camera, name string) (*PTZPreset, error)
+	// DeletePTZPresetCtx removes a saved position from a PTZ camera.
+	DeletePTZPresetCtx(ctx context.Context, camera *Camera, slot int) error
+	// StartPTZPatrolCtx starts a preset tour on a PTZ camera.
+	StartPTZPatrolCtx(ctx context.Context, camera *Camera, slot int) error
+	// StopPTZPatrolCtx stops the running preset tour on a PTZ camera.
+	StopPTZPatrolCtx(ctx context.Context, camera *Camera) error
+	// GetLightsCtx returns all of the Protect floodlights known to the NVR.
+	GetLightsCtx(ctx context.Context) ([]*Light, error)
+	// GetSensorsCtx returns all of the Protect sensors known to the NVR.
+	GetSensorsCtx(ctx context.Context) ([]*Sensor, error)
+	// GetChimesCtx returns all of the Protect chimes known to the NVR.
+	GetChimesCtx(ctx context.Context) ([]*Chime, error)
+	// GetDoorlocksCtx returns all of the Protect smart locks known to the NVR.
+	GetDoorlocksCtx(ctx context.Context) ([]*Doorlock, error)
+	// SetLightCtx turns a floodlight on, or back off.
+	SetLightCtx(ctx context.Context, lightID string, on bool) (*Light, error)
+	// SetLightBrightnessCtx sets a floodlight's brightness.
+	SetLightBrightnessCtx(ctx context.Context, lightID string, level int) (*Light, error)
+	// SetLightPIRSensitivityCtx sets how much motion turns on a floodlight.
+	SetLightPIRSensitivityCtx(ctx context.Context, lightID string, sensitivity int) (*Light, error)
+	// PlayChimeCtx rings a chime.
+	PlayChimeCtx(ctx context.Context, chimeID string, opts *ChimeOptions) error
+	// LockDoorlockCtx closes a smart lock.
+	LockDoorlockCtx(ctx context.Context, doorlockID string) error
+	// UnlockDoorlockCtx opens a smart lock.
+	UnlockDoorlockCtx(ctx context.Context, doorlockID string) error
+	// EnableRTSPCtx turns on RTSP streaming for a camera channel.
+	EnableRTSPCtx(ctx context.Context, cameraID string, channelID int) (*Camera, error)
+	// DisableRTSPCtx turns off RTSP streaming for a camera channel.
+	DisableRTSPCtx(ctx context.Context, cameraID string, channelID int) (*Camera, error)
+	// StreamURLsCtx returns the RTSP and RTSPS URLs for a camera's channels.
+	StreamURLsCtx(ctx context.Context, camera *Camera) ([]*StreamURL, error)
+	// GetNVRCtx returns the Protect NVR, with its storage and hardware health.
+	GetNVRCtx(ctx context.Context) (*NVR, error)
+	// GetRecordingCoverageCtx reports the recordings each camera has for a time window.
+	GetRecordingCoverageCtx(ctx context.Context, start, end time.Time) (*RecordingCoverage, error)
+	// ExportClipsCtx downloads the same time window from several cameras at once.
+	ExportClipsCtx(
+		ctx context.Context, cameraIDs []string, start, end time.Time, dir string, opts *ExportOptions,
+	) (*ExportManifest, error)
+}
+
 // Unifi is what you get in return for providing a password! Unifi represents
 // a controller that you can make authenticated requests to. Use this to make
 // additional requests for devices, clients or other custom data. Do not set
@@ -281,13 +510,21 @@ type Unifi struct {
 	*http.Client
 	*Config
 	*ServerStatus
+	transport    http.RoundTripper // the transport chain without authTransport.
+	log          *slog.Logger      // Config.Slog, or a wrapper for ErrorLog and DebugLog.
 	csrf         string
+	csrfMu       sync.RWMutex
+	authMu       sync.Mutex    // serializes re-authentication.
+	session      atomic.Uint64 // incremented on every successful login.
 	fingerprints fingerprints
 	new          bool
 }
 
-// ensure Unifi implements UnifiClient fully, will fail to compile otherwise
-var _ UnifiClient = &Unifi{}
+// ensure Unifi implements UnifiClient and UnifiClientCtx fully, will fail to compile otherwise
+var (
+	_ UnifiClient    = &Unifi{}
+	_ UnifiClientCtx = &Unifi{}
+)
 
 type fingerprints []string
 
@@ -658,6 +895,57 @@ func (f FlexTemp) Fake(faker *gofakeit.Faker) interface{} {
 	}
 }
 
+// FlexTime provides a container and unmarshalling for timestamps in the
+// Protect API. These are milliseconds since the epoch, as numbers or strings.
+// A null or zero timestamp becomes the zero time.Time.
+type FlexTime struct {
+	Val time.Time
+	Txt string
+}
+
+func NewFlexTime(v time.Time) *FlexTime {
+	return &FlexTime{
+		Val: v,
+		Txt: strconv.FormatInt(v.UnixMilli(), 10),
+	}
+}
+
+// UnmarshalJSON converts a string or number of milliseconds to a time.
+// Generally, do not call this directly, it's used in the json interface.
+func (f *FlexTime) UnmarshalJSON(b []byte) error {
+	var millis FlexInt
+	if err := millis.UnmarshalJSON(b); err != nil {
+		return err
+	}
+
+	f.Txt = millis.Txt
+	f.Val = time.Time{}
+
+	if millis.Val != 0 {
+		f.Val = time.UnixMilli(int64(millis.Val))
+	}
+
+	return nil
+}
+
+// MarshalJSON converts the time back to milliseconds, or null for the zero time.
+func (f FlexTime) MarshalJSON() ([]byte, error) {
+	if f.Val.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(f.Val.UnixMilli())
+}
+
+func (f *FlexTime) String() string {
+	return f.Txt
+}
+
+// Fake implements gofakeit Fake interface
+func (f FlexTime) Fake(faker *gofakeit.Faker) interface{} {
+	return *NewFlexTime(faker.DateRange(time.Now().AddDate(0, 0, -7), time.Now()).Round(time.Millisecond))
+}
+
 // DownlinkTable is part of a UXG and UDM output.
 type DownlinkTable struct {
 	PortIdx    FlexInt  `json:"port_idx"`
@@ -756,58 +1044,58 @@ type Port struct {
 }
 
 type Camera struct {
-	IsDeleting                bool   `json:"isDeleting"`
-	Mac                       string `json:"mac"`
-	Host                      string `json:"host"`
-	ConnectionHost            any    `json:"connectionHost"`
-	Type                      string `json:"type"`
-	Sysid                     any    `json:"sysid"`
-	Name                      string `json:"name"`
-	UpSince                   any    `json:"upSince"`
-	Uptime                    any    `json:"uptime"`
-	LastSeen                  int64  `json:"lastSeen"`
-	ConnectedSince            any    `json:"connectedSince"`
-	State                     string `json:"state"`
-	LastDisconnect            any    `json:"lastDisconnect"`
-	HardwareRevision          any    `json:"hardwareRevision"`
-	FirmwareVersion           any    `json:"firmwareVersion"`
-	LatestFirmwareVersion     any    `json:"latestFirmwareVersion"`
-	FirmwareBuild             any    `json:"firmwareBuild"`
-	IsUpdating                bool   `json:"isUpdating"`
-	IsDownloadingFW           bool   `json:"isDownloadingFW"`
-	FwUpdateState             string `json:"fwUpdateState"`
-	IsAdopting                bool   `json:"isAdopting"`
-	IsRestoring               bool   `json:"isRestoring"`
-	IsAdopted                 bool   `json:"isAdopted"`
-	IsAdoptedByOther          bool   `json:"isAdoptedByOther"`
-	IsProvisioned             bool   `json:"isProvisioned"`
-	IsRebooting               bool   `json:"isRebooting"`
-	IsSSHEnabled              bool   `json:"isSshEnabled"`
-	CanAdopt                  bool   `json:"canAdopt"`
-	IsAttemptingToConnect     bool   `json:"isAttemptingToConnect"`
-	UplinkDevice              any    `json:"uplinkDevice"`
-	GUID                      any    `json:"guid"`
-	AnonymousDeviceID         any    `json:"anonymousDeviceId"`
-	LastMotion                any    `json:"lastMotion"`
-	MicVolume                 int    `json:"micVolume"`
-	IsMicEnabled              bool   `json:"isMicEnabled"`
-	IsRecording               bool   `json:"isRecording"`
-	IsWirelessUplinkEnabled   bool   `json:"isWirelessUplinkEnabled"`
-	IsMotionDetected          bool   `json:"isMotionDetected"`
-	IsSmartDetected           bool   `json:"isSmartDetected"`
-	PhyRate                   any    `json:"phyRate"`
-	HdrMode                   bool   `json:"hdrMode"`
-	VideoMode                 string `json:"videoMode"`
-	IsProbingForWifi          bool   `json:"isProbingForWifi"`
-	ApMac                     any    `json:"apMac"`
-	ApRssi                    any    `json:"apRssi"`
-	ApMgmtIP                  any    `json:"apMgmtIp"`
-	ElementInfo               any    `json:"elementInfo"`
-	ChimeDuration             int    `json:"chimeDuration"`
-	IsDark                    bool   `json:"isDark"`
-	LastPrivacyZonePositionID any    `json:"lastPrivacyZonePositionId"`
-	LastRing                  any    `json:"lastRing"`
-	IsLiveHeatmapEnabled      bool   `json:"isLiveHeatmapEnabled"`
+	IsDeleting                bool            `json:"isDeleting"`
+	Mac                       string          `json:"mac"`
+	Host                      string          `json:"host"`
+	ConnectionHost            string          `json:"connectionHost"`
+	Type                      string          `json:"type"`
+	Sysid                     string          `json:"sysid"`
+	Name                      string          `json:"name"`
+	UpSince                   FlexTime        `json:"upSince"`
+	Uptime                    FlexInt         `json:"uptime"`
+	LastSeen                  FlexTime        `json:"lastSeen"`
+	ConnectedSince            FlexTime        `json:"connectedSince"`
+	State                     string          `json:"state"`
+	LastDisconnect            FlexTime        `json:"lastDisconnect"`
+	HardwareRevision          FlexInt         `json:"hardwareRevision"`
+	FirmwareVersion           string          `json:"firmwareVersion"`
+	LatestFirmwareVersion     string          `json:"latestFirmwareVersion"`
+	FirmwareBuild             string          `json:"firmwareBuild"`
+	IsUpdating                bool            `json:"isUpdating"`
+	IsDownloadingFW           bool            `json:"isDownloadingFW"`
+	FwUpdateState             string          `json:"fwUpdateState"`
+	IsAdopting                bool            `json:"isAdopting"`
+	IsRestoring               bool            `json:"isRestoring"`
+	IsAdopted                 bool            `json:"isAdopted"`
+	IsAdoptedByOther          bool            `json:"isAdoptedByOther"`
+	IsProvisioned             bool            `json:"isProvisioned"`
+	IsRebooting               bool            `json:"isRebooting"`
+	IsSSHEnabled              bool            `json:"isSshEnabled"`
+	CanAdopt                  bool            `json:"canAdopt"`
+	IsAttemptingToConnect     bool            `json:"isAttemptingToConnect"`
+	UplinkDevice              string          `json:"uplinkDevice"`
+	GUID                      string          `json:"guid"`
+	AnonymousDeviceID         string          `json:"anonymousDeviceId"`
+	LastMotion                FlexTime        `json:"lastMotion"`
+	MicVolume                 int             `json:"micVolume"`
+	IsMicEnabled              bool            `json:"isMicEnabled"`
+	IsRecording               bool            `json:"isRecording"`
+	IsWirelessUplinkEnabled   bool            `json:"isWirelessUplinkEnabled"`
+	IsMotionDetected          bool            `json:"isMotionDetected"`
+	IsSmartDetected           bool            `json:"isSmartDetected"`
+	PhyRate                   FlexInt         `json:"phyRate"`
+	HdrMode                   bool            `json:"hdrMode"`
+	VideoMode                 string          `json:"videoMode"`
+	IsProbingForWifi          bool            `json:"isProbingForWifi"`
+	ApMac                     string          `json:"apMac"`
+	ApRssi                    FlexInt         `json:"apRssi"`
+	ApMgmtIP                  string          `json:"apMgmtIp"`
+	ElementInfo               json.RawMessage `json:"elementInfo"`
+	ChimeDuration             int             `json:"chimeDuration"`
+	IsDark                    bool            `json:"isDark"`
+	LastPrivacyZonePositionID FlexInt         `json:"lastPrivacyZonePositionId"`
+	LastRing                  FlexTime        `json:"lastRing"`
+	IsLiveHeatmapEnabled      bool            `json:"isLiveHeatmapEnabled"`
 	EventStats                struct {
 		Motion struct {
 			Today       int   `json:"today"`
@@ -821,109 +1109,109 @@ type Camera struct {
 			LastDays []int `json:"lastDays"`
 		} `json:"smart"`
 	} `json:"eventStats"`
-	VideoReconfigurationInProgress bool   `json:"videoReconfigurationInProgress"`
-	Voltage                        any    `json:"voltage"`
-	ActivePatrolSlot               any    `json:"activePatrolSlot"`
-	UseGlobal                      bool   `json:"useGlobal"`
-	HubMac                         any    `json:"hubMac"`
-	IsPoorNetwork                  bool   `json:"isPoorNetwork"`
-	StopStreamLevel                any    `json:"stopStreamLevel"`
-	DownScaleMode                  int    `json:"downScaleMode"`
-	IsExtenderInstalledEver        bool   `json:"isExtenderInstalledEver"`
-	IsWaterproofCaseAttached       bool   `json:"isWaterproofCaseAttached"`
-	UserConfiguredAp               bool   `json:"userConfiguredAp"`
-	HasRecordings                  bool   `json:"hasRecordings"`
-	VideoCodec                     string `json:"videoCodec"`
-	VideoCodecState                int    `json:"videoCodecState"`
-	VideoCodecSwitchingSince       any    `json:"videoCodecSwitchingSince"`
-	EnableNfc                      bool   `json:"enableNfc"`
-	IsThirdPartyCamera             bool   `json:"isThirdPartyCamera"`
-	StreamingChannels              []int  `json:"streamingChannels"`
+	VideoReconfigurationInProgress bool     `json:"videoReconfigurationInProgress"`
+	Voltage                        FlexInt  `json:"voltage"`
+	ActivePatrolSlot               FlexInt  `json:"activePatrolSlot"`
+	UseGlobal                      bool     `json:"useGlobal"`
+	HubMac                         string   `json:"hubMac"`
+	IsPoorNetwork                  bool     `json:"isPoorNetwork"`
+	StopStreamLevel                FlexInt  `json:"stopStreamLevel"`
+	DownScaleMode                  int      `json:"downScaleMode"`
+	IsExtenderInstalledEver        bool     `json:"isExtenderInstalledEver"`
+	IsWaterproofCaseAttached       bool     `json:"isWaterproofCaseAttached"`
+	UserConfiguredAp               bool     `json:"userConfiguredAp"`
+	HasRecordings                  bool     `json:"hasRecordings"`
+	VideoCodec                     string   `json:"videoCodec"`
+	VideoCodecState                int      `json:"videoCodecState"`
+	VideoCodecSwitchingSince       FlexTime `json:"videoCodecSwitchingSince"`
+	EnableNfc                      bool     `json:"enableNfc"`
+	IsThirdPartyCamera             bool     `json:"isThirdPartyCamera"`
+	StreamingChannels              []int    `json:"streamingChannels"`
 	WiredConnectionState           struct {
-		PhyRate any `json:"phyRate"`
+		PhyRate FlexInt `json:"phyRate"`
 	} `json:"wiredConnectionState"`
 	WifiConnectionState struct {
-		Channel        any `json:"channel"`
-		Frequency      any `json:"frequency"`
-		PhyRate        any `json:"phyRate"`
-		TxRate         any `json:"txRate"`
-		SignalQuality  any `json:"signalQuality"`
-		Ssid           any `json:"ssid"`
-		Bssid          any `json:"bssid"`
-		ApName         any `json:"apName"`
-		Experience     any `json:"experience"`
-		SignalStrength any `json:"signalStrength"`
-		Connectivity   any `json:"connectivity"`
+		Channel        FlexInt `json:"channel"`
+		Frequency      FlexInt `json:"frequency"`
+		PhyRate        FlexInt `json:"phyRate"`
+		TxRate         FlexInt `json:"txRate"`
+		SignalQuality  FlexInt `json:"signalQuality"`
+		Ssid           string  `json:"ssid"`
+		Bssid          string  `json:"bssid"`
+		ApName         string  `json:"apName"`
+		Experience     FlexInt `json:"experience"`
+		SignalStrength FlexInt `json:"signalStrength"`
+		Connectivity   string  `json:"connectivity"`
 	} `json:"wifiConnectionState"`
 	Channels []struct {
-		ID                       int    `json:"id"`
-		VideoID                  string `json:"videoId"`
-		Name                     string `json:"name"`
-		Enabled                  bool   `json:"enabled"`
-		IsRtspEnabled            bool   `json:"isRtspEnabled"`
-		RtspAlias                string `json:"rtspAlias"`
-		Width                    int    `json:"width"`
-		Height                   int    `json:"height"`
-		Fps                      int    `json:"fps"`
-		Bitrate                  int64  `json:"bitrate"`
-		MinBitrate               any    `json:"minBitrate"`
-		MaxBitrate               any    `json:"maxBitrate"`
-		MinClientAdaptiveBitRate any    `json:"minClientAdaptiveBitRate"`
-		MinMotionAdaptiveBitRate any    `json:"minMotionAdaptiveBitRate"`
-		FpsValues                []int  `json:"fpsValues"`
-		IdrInterval              int    `json:"idrInterval"`
-		AutoFps                  bool   `json:"autoFps"`
-		AutoBitrate              bool   `json:"autoBitrate"`
+		ID                       int     `json:"id"`
+		VideoID                  string  `json:"videoId"`
+		Name                     string  `json:"name"`
+		Enabled                  bool    `json:"enabled"`
+		IsRtspEnabled            bool    `json:"isRtspEnabled"`
+		RtspAlias                string  `json:"rtspAlias"`
+		Width                    int     `json:"width"`
+		Height                   int     `json:"height"`
+		Fps                      int     `json:"fps"`
+		Bitrate                  int64   `json:"bitrate"`
+		MinBitrate               FlexInt `json:"minBitrate"`
+		MaxBitrate               FlexInt `json:"maxBitrate"`
+		MinClientAdaptiveBitRate FlexInt `json:"minClientAdaptiveBitRate"`
+		MinMotionAdaptiveBitRate FlexInt `json:"minMotionAdaptiveBitRate"`
+		FpsValues                []int   `json:"fpsValues"`
+		IdrInterval              int     `json:"idrInterval"`
+		AutoFps                  bool    `json:"autoFps"`
+		AutoBitrate              bool    `json:"autoBitrate"`
 	} `json:"channels"`
 	IspSettings struct {
-		AeMode                         string `json:"aeMode"`
-		IrLedMode                      string `json:"irLedMode"`
-		IrLedLevel                     int    `json:"irLedLevel"`
-		Wdr                            int    `json:"wdr"`
-		IcrSensitivity                 int    `json:"icrSensitivity"`
-		IcrSwitchMode                  string `json:"icrSwitchMode"`
-		IcrCustomValue                 int    `json:"icrCustomValue"`
-		Brightness                     int    `json:"brightness"`
-		Contrast                       int    `json:"contrast"`
-		Hue                            int    `json:"hue"`
-		Saturation                     int    `json:"saturation"`
-		Sharpness                      int    `json:"sharpness"`
-		Denoise                        int    `json:"denoise"`
-		IsColorNightVisionEnabled      bool   `json:"isColorNightVisionEnabled"`
-		SpotlightDuration              int    `json:"spotlightDuration"`
-		IsFlippedVertical              bool   `json:"isFlippedVertical"`
-		IsFlippedHorizontal            bool   `json:"isFlippedHorizontal"`
-		IsAutoRotateEnabled            bool   `json:"isAutoRotateEnabled"`
-		IsLdcEnabled                   bool   `json:"isLdcEnabled"`
-		Is3DnrEnabled                  bool   `json:"is3dnrEnabled"`
-		IsExternalIrEnabled            bool   `json:"isExternalIrEnabled"`
-		IsAggressiveAntiFlickerEnabled bool   `json:"isAggressiveAntiFlickerEnabled"`
-		IsPauseMotionEnabled           bool   `json:"isPauseMotionEnabled"`
-		DZoomCenterX                   int    `json:"dZoomCenterX"`
-		DZoomCenterY                   int    `json:"dZoomCenterY"`
-		DZoomScale                     int    `json:"dZoomScale"`
-		DZoomStreamID                  int    `json:"dZoomStreamId"`
-		FocusPosition                  int    `json:"focusPosition"`
-		TouchFocusX                    any    `json:"touchFocusX"`
-		TouchFocusY                    any    `json:"touchFocusY"`
-		ZoomPosition                   int    `json:"zoomPosition"`
-		MountPosition                  any    `json:"mountPosition"`
-		HdrMode                        string `json:"hdrMode"`
+		AeMode                         string  `json:"aeMode"`
+		IrLedMode                      string  `json:"irLedMode"`
+		IrLedLevel                     int     `json:"irLedLevel"`
+		Wdr                            int     `json:"wdr"`
+		IcrSensitivity                 int     `json:"icrSensitivity"`
+		IcrSwitchMode                  string  `json:"icrSwitchMode"`
+		IcrCustomValue                 int     `json:"icrCustomValue"`
+		Brightness                     int     `json:"brightness"`
+		Contrast                       int     `json:"contrast"`
+		Hue                            int     `json:"hue"`
+		Saturation                     int     `json:"saturation"`
+		Sharpness                      int     `json:"sharpness"`
+		Denoise                        int     `json:"denoise"`
+		IsColorNightVisionEnabled      bool    `json:"isColorNightVisionEnabled"`
+		SpotlightDuration              int     `json:"spotlightDuration"`
+		IsFlippedVertical              bool    `json:"isFlippedVertical"`
+		IsFlippedHorizontal            bool    `json:"isFlippedHorizontal"`
+		IsAutoRotateEnabled            bool    `json:"isAutoRotateEnabled"`
+		IsLdcEnabled                   bool    `json:"isLdcEnabled"`
+		Is3DnrEnabled                  bool    `json:"is3dnrEnabled"`
+		IsExternalIrEnabled            bool    `json:"isExternalIrEnabled"`
+		IsAggressiveAntiFlickerEnabled bool    `json:"isAggressiveAntiFlickerEnabled"`
+		IsPauseMotionEnabled           bool    `json:"isPauseMotionEnabled"`
+		DZoomCenterX                   int     `json:"dZoomCenterX"`
+		DZoomCenterY                   int     `json:"dZoomCenterY"`
+		DZoomScale                     int     `json:"dZoomScale"`
+		DZoomStreamID                  int     `json:"dZoomStreamId"`
+		FocusPosition                  int     `json:"focusPosition"`
+		TouchFocusX                    FlexInt `json:"touchFocusX"`
+		TouchFocusY                    FlexInt `json:"touchFocusY"`
+		ZoomPosition                   int     `json:"zoomPosition"`
+		MountPosition                  string  `json:"mountPosition"`
+		HdrMode                        string  `json:"hdrMode"`
 	} `json:"ispSettings"`
 	AudioSettings struct {
 		Style []string `json:"style"`
 	} `json:"audioSettings"`
 	TalkbackSettings struct {
-		TypeFmt       string `json:"typeFmt"`
-		TypeIn        string `json:"typeIn"`
-		BindAddr      string `json:"bindAddr"`
-		BindPort      int    `json:"bindPort"`
-		FilterAddr    any    `json:"filterAddr"`
-		FilterPort    any    `json:"filterPort"`
-		Channels      int    `json:"channels"`
-		SamplingRate  int    `json:"samplingRate"`
-		BitsPerSample int    `json:"bitsPerSample"`
-		Quality       int    `json:"quality"`
+		TypeFmt       string  `json:"typeFmt"`
+		TypeIn        string  `json:"typeIn"`
+		BindAddr      string  `json:"bindAddr"`
+		BindPort      int     `json:"bindPort"`
+		FilterAddr    string  `json:"filterAddr"`
+		FilterPort    FlexInt `json:"filterPort"`
+		Channels      int     `json:"channels"`
+		SamplingRate  int     `json:"samplingRate"`
+		BitsPerSample int     `json:"bitsPerSample"`
+		Quality       int     `json:"quality"`
 	} `json:"talkbackSettings"`
 	OsdSettings struct {
 		IsNameEnabled  bool `json:"isNameEnabled"`
@@ -957,42 +1245,42 @@ type Camera struct {
 		UseNewMotionAlgorithm      bool   `json:"useNewMotionAlgorithm"`
 	} `json:"recordingSettings"`
 	SmartDetectSettings struct {
-		ObjectTypes             []any `json:"objectTypes"`
-		AutoTrackingObjectTypes []any `json:"autoTrackingObjectTypes"`
-		AudioTypes              []any `json:"audioTypes"`
+		ObjectTypes             []SmartDetectType      `json:"objectTypes"`
+		AutoTrackingObjectTypes []SmartDetectType      `json:"autoTrackingObjectTypes"`
+		AudioTypes              []SmartDetectAudioType `json:"audioTypes"`
 		DetectionRange          struct {
-			Max any `json:"max"`
-			Min any `json:"min"`
+			Max FlexInt `json:"max"`
+			Min FlexInt `json:"min"`
 		} `json:"detectionRange"`
 	} `json:"smartDetectSettings"`
-	RecordingSchedulesV2 []any `json:"recordingSchedulesV2"`
-	MotionZones          []any `json:"motionZones"`
-	PrivacyZones         []any `json:"privacyZones"`
-	SmartDetectZones     []any `json:"smartDetectZones"`
-	SmartDetectLines     []any `json:"smartDetectLines"`
+	RecordingSchedulesV2 []json.RawMessage `json:"recordingSchedulesV2"`
+	MotionZones          []CameraZone      `json:"motionZones"`
+	PrivacyZones         []CameraZone      `json:"privacyZones"`
+	SmartDetectZones     []SmartDetectZone `json:"smartDetectZones"`
+	SmartDetectLines     []SmartDetectLine `json:"smartDetectLines"`
 	Stats                struct {
 		RxBytes int `json:"rxBytes"`
 		TxBytes int `json:"txBytes"`
 		Wifi    struct {
-			Channel        any `json:"channel"`
-			Frequency      any `json:"frequency"`
-			LinkSpeedMbps  any `json:"linkSpeedMbps"`
-			SignalQuality  int `json:"signalQuality"`
-			SignalStrength int `json:"signalStrength"`
+			Channel        FlexInt `json:"channel"`
+			Frequency      FlexInt `json:"frequency"`
+			LinkSpeedMbps  FlexInt `json:"linkSpeedMbps"`
+			SignalQuality  int     `json:"signalQuality"`
+			SignalStrength int     `json:"signalStrength"`
 		} `json:"wifi"`
 		Video struct {
-			RecordingStart   int64 `json:"recordingStart"`
-			RecordingEnd     int64 `json:"recordingEnd"`
-			RecordingStartLQ any   `json:"recordingStartLQ"`
-			RecordingEndLQ   any   `json:"recordingEndLQ"`
-			TimelapseStart   any   `json:"timelapseStart"`
-			TimelapseEnd     any   `json:"timelapseEnd"`
-			TimelapseStartLQ any   `json:"timelapseStartLQ"`
-			TimelapseEndLQ   any   `json:"timelapseEndLQ"`
+			RecordingStart   FlexTime `json:"recordingStart"`
+			RecordingEnd     FlexTime `json:"recordingEnd"`
+			RecordingStartLQ FlexTime `json:"recordingStartLQ"`
+			RecordingEndLQ   FlexTime `json:"recordingEndLQ"`
+			TimelapseStart   FlexTime `json:"timelapseStart"`
+			TimelapseEnd     FlexTime `json:"timelapseEnd"`
+			TimelapseStartLQ FlexTime `json:"timelapseStartLQ"`
+			TimelapseEndLQ   FlexTime `json:"timelapseEndLQ"`
 		} `json:"video"`
 		Storage struct {
-			Used           any `json:"used"`
-			Rate           any `json:"rate"`
+			Used           FlexInt `json:"used"`
+			Rate           FlexInt `json:"rate"`
 			ChannelStorage struct {
 				Num0 struct {
 					Rotating struct {
@@ -1010,123 +1298,91 @@ type Camera struct {
 		WifiStrength int `json:"wifiStrength"`
 	} `json:"stats"`
 	FeatureFlags struct {
-		CanAdjustIrLedLevel     bool  `json:"canAdjustIrLedLevel"`
-		CanMagicZoom            bool  `json:"canMagicZoom"`
-		CanOpticalZoom          bool  `json:"canOpticalZoom"`
-		CanTouchFocus           bool  `json:"canTouchFocus"`
-		HasAccelerometer        bool  `json:"hasAccelerometer"`
-		HasVerticalFlip         bool  `json:"hasVerticalFlip"`
-		HasAec                  bool  `json:"hasAec"`
-		HasBluetooth            bool  `json:"hasBluetooth"`
-		HasChime                bool  `json:"hasChime"`
-		HasExternalIr           bool  `json:"hasExternalIr"`
-		HasIcrSensitivity       bool  `json:"hasIcrSensitivity"`
-		HasInfrared             bool  `json:"hasInfrared"`
-		HasLdc                  bool  `json:"hasLdc"`
-		HasLedIr                bool  `json:"hasLedIr"`
-		HasLedStatus            bool  `json:"hasLedStatus"`
-		HasLineIn               bool  `json:"hasLineIn"`
-		HasMic                  bool  `json:"hasMic"`
-		HasPrivacyMask          bool  `json:"hasPrivacyMask"`
-		HasRtc                  bool  `json:"hasRtc"`
-		HasSdCard               bool  `json:"hasSdCard"`
-		HasSpeaker              bool  `json:"hasSpeaker"`
-		HasWifi                 bool  `json:"hasWifi"`
-		HasHdr                  bool  `json:"hasHdr"`
-		HasAutoICROnly          bool  `json:"hasAutoICROnly"`
-		VideoModes              []any `json:"videoModes"`
-		VideoModeMaxFps         []any `json:"videoModeMaxFps"`
-		HasMotionZones          bool  `json:"hasMotionZones"`
-		HasLcdScreen            bool  `json:"hasLcdScreen"`
-		MountPositions          []any `json:"mountPositions"`
-		SmartDetectTypes        []any `json:"smartDetectTypes"`
-		SmartDetectAudioTypes   []any `json:"smartDetectAudioTypes"`
-		SupportDoorAccessConfig bool  `json:"supportDoorAccessConfig"`
-		SupportNfc              bool  `json:"supportNfc"`
-		LensType                any   `json:"lensType"`
-		LensModel               any   `json:"lensModel"`
-		MotionAlgorithms        []any `json:"motionAlgorithms"`
-		HasSquareEventThumbnail bool  `json:"hasSquareEventThumbnail"`
-		HasPackageCamera        bool  `json:"hasPackageCamera"`
-		Audio                   []any `json:"audio"`
-		AudioCodecs             []any `json:"audioCodecs"`
-		VideoCodecs             []any `json:"videoCodecs"`
-		AudioStyle              []any `json:"audioStyle"`
-		IsDoorbell              bool  `json:"isDoorbell"`
-		IsPtz                   bool  `json:"isPtz"`
-		HasColorLcdScreen       bool  `json:"hasColorLcdScreen"`
-		HasLiveviewTracking     bool  `json:"hasLiveviewTracking"`
-		HasLineCrossing         bool  `json:"hasLineCrossing"`
-		HasLineCrossingCounting bool  `json:"hasLineCrossingCounting"`
-		HasFlash                bool  `json:"hasFlash"`
-		FlashRange              any   `json:"flashRange"`
-		HasLuxCheck             bool  `json:"hasLuxCheck"`
-		PresetTour              bool  `json:"presetTour"`
+		CanAdjustIrLedLevel     bool                   `json:"canAdjustIrLedLevel"`
+		CanMagicZoom            bool                   `json:"canMagicZoom"`
+		CanOpticalZoom          bool                   `json:"canOpticalZoom"`
+		CanTouchFocus           bool                   `json:"canTouchFocus"`
+		HasAccelerometer        bool                   `json:"hasAccelerometer"`
+		HasVerticalFlip         bool                   `json:"hasVerticalFlip"`
+		HasAec                  bool                   `json:"hasAec"`
+		HasBluetooth            bool                   `json:"hasBluetooth"`
+		HasChime                bool                   `json:"hasChime"`
+		HasExternalIr           bool                   `json:"hasExternalIr"`
+		HasIcrSensitivity       bool                   `json:"hasIcrSensitivity"`
+		HasInfrared             bool                   `json:"hasInfrared"`
+		HasLdc                  bool                   `json:"hasLdc"`
+		HasLedIr                bool                   `json:"hasLedIr"`
+		HasLedStatus            bool                   `json:"hasLedStatus"`
+		HasLineIn               bool                   `json:"hasLineIn"`
+		HasMic                  bool                   `json:"hasMic"`
+		HasPrivacyMask          bool                   `json:"hasPrivacyMask"`
+		HasRtc                  bool                   `json:"hasRtc"`
+		HasSdCard               bool                   `json:"hasSdCard"`
+		HasSpeaker              bool                   `json:"hasSpeaker"`
+		HasWifi                 bool                   `json:"hasWifi"`
+		HasHdr                  bool                   `json:"hasHdr"`
+		HasAutoICROnly          bool                   `json:"hasAutoICROnly"`
+		VideoModes              []string               `json:"videoModes"`
+		VideoModeMaxFps         []FlexInt              `json:"videoModeMaxFps"`
+		HasMotionZones          bool                   `json:"hasMotionZones"`
+		HasLcdScreen            bool                   `json:"hasLcdScreen"`
+		MountPositions          []string               `json:"mountPositions"`
+		SmartDetectTypes        []SmartDetectType      `json:"smartDetectTypes"`
+		SmartDetectAudioTypes   []SmartDetectAudioType `json:"smartDetectAudioTypes"`
+		SupportDoorAccessConfig bool                   `json:"supportDoorAccessConfig"`
+		SupportNfc              bool                   `json:"supportNfc"`
+		LensType                string                 `json:"lensType"`
+		LensModel               string                 `json:"lensModel"`
+		MotionAlgorithms        []string               `json:"motionAlgorithms"`
+		HasSquareEventThumbnail bool                   `json:"hasSquareEventThumbnail"`
+		HasPackageCamera        bool                   `json:"hasPackageCamera"`
+		Audio                   []string               `json:"audio"`
+		AudioCodecs             []string               `json:"audioCodecs"`
+		VideoCodecs             []string               `json:"videoCodecs"`
+		AudioStyle              []string               `json:"audioStyle"`
+		IsDoorbell              bool                   `json:"isDoorbell"`
+		IsPtz                   bool                   `json:"isPtz"`
+		HasColorLcdScreen       bool                   `json:"hasColorLcdScreen"`
+		HasLiveviewTracking     bool                   `json:"hasLiveviewTracking"`
+		HasLineCrossing         bool                   `json:"hasLineCrossing"`
+		HasLineCrossingCounting bool                   `json:"hasLineCrossingCounting"`
+		HasFlash                bool                   `json:"hasFlash"`
+		FlashRange              FlexInt                `json:"flashRange"`
+		HasLuxCheck             bool                   `json:"hasLuxCheck"`
+		PresetTour              bool                   `json:"presetTour"`
 		PrivacyMaskCapability   struct {
-			MaxMasks      any  `json:"maxMasks"`
-			RectangleOnly bool `json:"rectangleOnly"`
+			MaxMasks      FlexInt `json:"maxMasks"`
+			RectangleOnly bool    `json:"rectangleOnly"`
 		} `json:"privacyMaskCapability"`
 		Focus struct {
-			Steps struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"steps"`
-			Degrees struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"degrees"`
+			Steps   FeatureRange `json:"steps"`
+			Degrees FeatureRange `json:"degrees"`
 		} `json:"focus"`
 		Pan struct {
-			Steps struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"steps"`
-			Degrees struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"degrees"`
+			Steps   FeatureRange `json:"steps"`
+			Degrees FeatureRange `json:"degrees"`
 		} `json:"pan"`
 		Tilt struct {
-			Steps struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"steps"`
-			Degrees struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"degrees"`
+			Steps   FeatureRange `json:"steps"`
+			Degrees FeatureRange `json:"degrees"`
 		} `json:"tilt"`
 		Zoom struct {
-			Ratio int `json:"ratio"`
-			Steps struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"steps"`
-			Degrees struct {
-				Max  any `json:"max"`
-				Min  any `json:"min"`
-				Step any `json:"step"`
-			} `json:"degrees"`
+			Ratio   int          `json:"ratio"`
+			Steps   FeatureRange `json:"steps"`
+			Degrees FeatureRange `json:"degrees"`
 		} `json:"zoom"`
 		Hotplug struct {
-			Audio              any  `json:"audio"`
-			Video              any  `json:"video"`
-			StandaloneAdoption bool `json:"standaloneAdoption"`
+			Audio              FlexBool `json:"audio"`
+			Video              FlexBool `json:"video"`
+			StandaloneAdoption bool     `json:"standaloneAdoption"`
 			Extender           struct {
-				IsAttached    any `json:"isAttached"`
-				HasFlash      any `json:"hasFlash"`
-				FlashRange    any `json:"flashRange"`
-				HasIR         any `json:"hasIR"`
-				HasRadar      any `json:"hasRadar"`
-				RadarRangeMax any `json:"radarRangeMax"`
-				RadarRangeMin any `json:"radarRangeMin"`
+				IsAttached    FlexBool `json:"isAttached"`
+				HasFlash      FlexBool `json:"hasFlash"`
+				FlashRange    FlexInt  `json:"flashRange"`
+				HasIR         FlexBool `json:"hasIR"`
+				HasRadar      FlexBool `json:"hasRadar"`
+				RadarRangeMax FlexInt  `json:"radarRangeMax"`
+				RadarRangeMin FlexInt  `json:"radarRangeMin"`
 			} `json:"extender"`
 		} `json:"hotplug"`
 		HasSmartDetect bool `json:"hasSmartDetect"`
@@ -1136,16 +1392,19 @@ type Camera struct {
 		Limit int    `json:"limit"`
 	} `json:"tiltLimitsOfPrivacyZones"`
 	LcdMessage struct {
+		Type    string   `json:"type"`
+		Text    string   `json:"text"`
+		ResetAt FlexTime `json:"resetAt"`
 	} `json:"lcdMessage"`
-	Lenses        []any `json:"lenses"`
+	Lenses        []json.RawMessage `json:"lenses"`
 	StreamSharing struct {
-		Enabled        bool `json:"enabled"`
-		Token          any  `json:"token"`
-		ShareLink      any  `json:"shareLink"`
-		Expires        any  `json:"expires"`
-		SharedByUserID any  `json:"sharedByUserId"`
-		SharedByUser   any  `json:"sharedByUser"`
-		MaxStreams     any  `json:"maxStreams"`
+		Enabled        bool     `json:"enabled"`
+		Token          string   `json:"token"`
+		ShareLink      string   `json:"shareLink"`
+		Expires        FlexTime `json:"expires"`
+		SharedByUserID string   `json:"sharedByUserId"`
+		SharedByUser   string   `json:"sharedByUser"`
+		MaxStreams     FlexInt  `json:"maxStreams"`
 	} `json:"streamSharing"`
 	HomekitSettings struct {
 		TalkbackSettingsActive bool `json:"talkbackSettingsActive"`
@@ -1153,29 +1412,29 @@ type Camera struct {
 		MicrophoneMuted        bool `json:"microphoneMuted"`
 		SpeakerMuted           bool `json:"speakerMuted"`
 	} `json:"homekitSettings"`
-	Shortcuts []any `json:"shortcuts"`
+	Shortcuts []json.RawMessage `json:"shortcuts"`
 	Alarms    struct {
-		LensThermal                         int   `json:"lensThermal"`
-		TiltThermal                         int   `json:"tiltThermal"`
-		PanTiltMotorFaults                  []any `json:"panTiltMotorFaults"`
-		AutoTrackingThermalThresholdReached bool  `json:"autoTrackingThermalThresholdReached"`
-		LensThermalThresholdReached         bool  `json:"lensThermalThresholdReached"`
-		MotorOverheated                     bool  `json:"motorOverheated"`
+		LensThermal                         int      `json:"lensThermal"`
+		TiltThermal                         int      `json:"tiltThermal"`
+		PanTiltMotorFaults                  []string `json:"panTiltMotorFaults"`
+		AutoTrackingThermalThresholdReached bool     `json:"autoTrackingThermalThresholdReached"`
+		LensThermalThresholdReached         bool     `json:"lensThermalThresholdReached"`
+		MotorOverheated                     bool     `json:"motorOverheated"`
 	} `json:"alarms"`
 	ExtendedAiFeatures struct {
-		SmartDetectTypes []any `json:"smartDetectTypes"`
+		SmartDetectTypes []SmartDetectType `json:"smartDetectTypes"`
 	} `json:"extendedAiFeatures"`
 	ThirdPartyCameraInfo struct {
 		Port        FlexInt `json:"port"`
 		RtspURL     string  `json:"rtspUrl"`
-		RtspURLLQ   any     `json:"rtspUrlLQ"`
+		RtspURLLQ   string  `json:"rtspUrlLQ"`
 		SnapshotURL string  `json:"snapshotUrl"`
 	} `json:"thirdPartyCameraInfo"`
 	ID                          string   `json:"id"`
 	NvrMac                      string   `json:"nvrMac"`
 	DisplayName                 string   `json:"displayName"`
 	IsConnected                 bool     `json:"isConnected"`
-	Platform                    any      `json:"platform"`
+	Platform                    string   `json:"platform"`
 	HasSpeaker                  bool     `json:"hasSpeaker"`
 	HasWifi                     bool     `json:"hasWifi"`
 	AudioBitrate                int      `json:"audioBitrate"`
@@ -1188,3 +1447,69 @@ type Camera struct {
 	SupportedScalingResolutions []string `json:"supportedScalingResolutions"`
 	ModelKey                    string   `json:"modelKey"`
 }
+
+// SmartDetectType is an object type recognized by a camera's smart detection.
+type SmartDetectType string
+
+// These are the smart detection object types.
+const (
+	SmartDetectPerson       SmartDetectType = "person"
+	SmartDetectVehicle      SmartDetectType = "vehicle"
+	SmartDetectPackage      SmartDetectType = "package"
+	SmartDetectAnimal       SmartDetectType = "animal"
+	SmartDetectFace         SmartDetectType = "face"
+	SmartDetectLicensePlate SmartDetectType = "licensePlate"
+)
+
+// SmartDetectAudioType is a sound recognized by a camera's smart detection.
+type SmartDetectAudioType string
+
+// These are the smart detection audio types.
+const (
+	SmartDetectAudioSmokeCmonx SmartDetectAudioType = "smoke_cmonx"
+	SmartDetectAudioSmoke      SmartDetectAudioType = "alrmSmoke"
+	SmartDetectAudioCmonx      SmartDetectAudioType = "alrmCmonx"
+	SmartDetectAudioSiren      SmartDetectAudioType = "alrmSiren"
+	SmartDetectAudioBabyCry    SmartDetectAudioType = "alrmBabyCry"
+	SmartDetectAudioSpeak      SmartDetectAudioType = "alrmSpeak"
+	SmartDetectAudioBark       SmartDetectAudioType = "alrmBark"
+	SmartDetectAudioBurglar    SmartDetectAudioType = "alrmBurglar"
+	SmartDetectAudioCarHorn    SmartDetectAudioType = "alrmCarHorn"
+	SmartDetectAudioGlassBreak SmartDetectAudioType = "alrmGlassBreak"
+)
+
+// ZonePoint is an x, y position in a camera's field of view. Each is from 0 to 1.
+type ZonePoint [2]float64
+
+// CameraZone is a motion or privacy zone; a polygon in a camera's field of view.
+type CameraZone struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Color       string      `json:"color"`
+	Points      []ZonePoint `json:"points"`
+	Sensitivity int         `json:"sensitivity"`
+}
+
+// SmartDetectZone is a zone where smart detection looks for the listed object types.
+type SmartDetectZone struct {
+	CameraZone
+	ObjectTypes           []SmartDetectType `json:"objectTypes"`
+	IsTriggerLightEnabled bool              `json:"isTriggerLightEnabled"`
+}
+
+// SmartDetectLine is a line that triggers smart detection when crossed by the listed object types.
+type SmartDetectLine struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Color       string            `json:"color"`
+	Points      []ZonePoint       `json:"points"`
+	ObjectTypes []SmartDetectType `json:"objectTypes"`
+}
+
+// FeatureRange is the range of a camera capability, like pan steps or zoom degrees.
+// All values are zero when the camera does not have the capability.
+type FeatureRange struct {
+	Max  FlexInt `json:"max"`
+	Min  FlexInt `json:"min"`
+	Step FlexInt `json:"step"`
+}
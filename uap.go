@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -634,22 +635,21 @@ type RogueAP struct {
 // GetRogueAPs returns RogueAPs for a list of Sites.
 // Use GetRogueAPsSite if you want more control.
 func (u *Unifi) GetRogueAPs(sites []*Site) ([]*RogueAP, error) {
-	data := []*RogueAP{}
-
-	for _, site := range sites {
-		response, err := u.GetRogueAPsSite(site)
-		if err != nil {
-			return data, err
-		}
-
-		data = append(data, response...)
-	}
+	return u.GetRogueAPsCtx(context.Background(), sites)
+}
 
-	return data, nil
+// GetRogueAPsCtx is the same as GetRogueAPs, but uses the provided context.
+func (u *Unifi) GetRogueAPsCtx(ctx context.Context, sites []*Site) ([]*RogueAP, error) {
+	return eachSite(ctx, u, sites, u.GetRogueAPsSiteCtx)
 }
 
 // GetRogueAPsSite returns RogueAPs for a single Site.
 func (u *Unifi) GetRogueAPsSite(site *Site) ([]*RogueAP, error) {
+	return u.GetRogueAPsSiteCtx(context.Background(), site)
+}
+
+// GetRogueAPsSiteCtx is the same as GetRogueAPsSite, but uses the provided context.
+func (u *Unifi) GetRogueAPsSiteCtx(ctx context.Context, site *Site) ([]*RogueAP, error) {
 	if site == nil || site.Name == "" {
 		return nil, ErrNoSiteProvided
 	}
@@ -663,7 +663,7 @@ func (u *Unifi) GetRogueAPsSite(site *Site) ([]*RogueAP, error) {
 		}
 	)
 
-	if err := u.GetData(path, &rogueaps, ""); err != nil {
+	if err := u.GetDataCtx(ctx, path, &rogueaps, ""); err != nil {
 		return rogueaps.Data, err
 	}
 
@@ -10,17 +10,15 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -30,14 +28,28 @@ import (
 var (
 	ErrAuthenticationFailed = fmt.Errorf("authentication failed")
 	ErrInvalidStatusCode    = fmt.Errorf("invalid status code from server")
-	ErrNoParams             = fmt.Errorf("requested PUT with no parameters")
+	ErrNoParams             = fmt.Errorf("requested PUT or PATCH with no parameters")
 	ErrInvalidSignature     = fmt.Errorf("certificate signature does not match")
+	ErrPinningUnsupported   = fmt.Errorf("SSLCert requires an *http.Transport RoundTripper")
 )
 
+// APIKeyHeader is the header used to send Config.APIKey to UniFi OS consoles.
+const APIKeyHeader = "X-API-KEY"
+
 // NewUnifi creates a http.Client with authenticated cookies.
 // Used to make additional, authenticated requests to the APIs.
 // Start here.
 func NewUnifi(config *Config) (*Unifi, error) {
+	return NewUnifiCtx(context.Background(), config)
+}
+
+// NewUnifiCtx is the same as NewUnifi, but the initial API check and login
+// requests are bound to the provided context.
+func NewUnifiCtx(ctx context.Context, config *Config) (*Unifi, error) {
+	if _, ok := config.RoundTripper.(*http.Transport); len(config.SSLCert) > 0 && config.RoundTripper != nil && !ok {
+		return nil, fmt.Errorf("%w, not %T", ErrPinningUnsupported, config.RoundTripper)
+	}
+
 	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 	if err != nil {
 		return nil, fmt.Errorf("creating cookiejar: %w", err)
@@ -50,11 +62,16 @@ func NewUnifi(config *Config) (*Unifi, error) {
 		u.fingerprints[i] = fmt.Sprintf("%x", sha256.Sum256(p.Bytes))
 	}
 
-	if err := u.checkNewStyleAPI(); err != nil {
+	if err := u.checkNewStyleAPI(ctx); err != nil {
 		return u, err
 	}
 
-	if err := u.Login(); err != nil {
+	if config.APIKey != "" {
+		// API keys are sent with every request; there is no session to create.
+		return u, nil
+	}
+
+	if err := u.LoginCtx(ctx); err != nil {
 		return u, err
 	}
 
@@ -68,37 +85,22 @@ func NewUnifi(config *Config) (*Unifi, error) {
 func newUnifi(config *Config, jar http.CookieJar) *Unifi {
 	config.URL = strings.TrimRight(config.URL, "/")
 
-	if config.ErrorLog == nil {
-		config.ErrorLog = discardLogs
-	}
-
-	if config.DebugLog == nil {
-		config.DebugLog = discardLogs
-	}
-
 	u := &Unifi{
 		Config: config,
 		Client: &http.Client{
 			Timeout: config.Timeout,
 			Jar:     jar,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: !config.VerifySSL, // nolint: gosec
-				},
-			},
 		},
 	}
 
+	u.setupLogs()
+
 	if len(config.SSLCert) > 0 {
 		u.fingerprints = make(fingerprints, len(config.SSLCert))
-		u.Client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify:    true, // nolint: gosec
-				VerifyPeerCertificate: u.verifyPeerCertificate,
-			},
-		}
 	}
 
+	u.Client.Transport, u.transport = u.newTransport()
+
 	return u
 }
 
@@ -118,36 +120,121 @@ func (u *Unifi) verifyPeerCertificate(certs [][]byte, _ [][]*x509.Certificate) e
 
 // Login is a helper method. It can be called to grab a new authentication cookie.
 func (u *Unifi) Login() error {
-	start := time.Now()
+	return u.LoginCtx(context.Background())
+}
 
-	// magic login.
-	req, err := u.UniReq(APILoginPath, fmt.Sprintf(`{"username":"%s","password":"%s"}`, u.User, u.Pass))
+// LoginCtx is the same as Login, but the request is bound to the provided context.
+func (u *Unifi) LoginCtx(ctx context.Context) error {
+	resp, body, err := u.login(ctx, "")
 	if err != nil {
 		return err
 	}
 
-	resp, err := u.Do(req)
-	if err != nil {
-		return fmt.Errorf("making request: %w", err)
-	}
+	if resp.StatusCode != http.StatusOK && mfaRequired(resp.StatusCode, body) {
+		token, err := u.mfaToken()
+		if err != nil {
+			return fmt.Errorf("(user: %s): %s (status: %s): %w",
+				u.User, resp.Request.URL, resp.Status, err)
+		}
 
-	defer resp.Body.Close()               // we need no data here.
-	_, _ = io.Copy(io.Discard, resp.Body) // avoid leaking.
-	u.DebugLog("Requested %s: elapsed %v, returned %d bytes",
-		req.URL, time.Since(start).Round(time.Millisecond), resp.ContentLength)
+		if resp, _, err = u.login(ctx, token); err != nil {
+			return err
+		}
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("(user: %s): %s (status: %s): %w",
-			u.User, req.URL, resp.Status, ErrAuthenticationFailed)
+			u.User, resp.Request.URL, resp.Status, ErrAuthenticationFailed)
 	}
 
+	u.session.Add(1)
+
 	return nil
 }
 
+// loginRequest is the body posted to the login path.
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token,omitempty"`          // MFA token, UniFi OS.
+	Ubic2FA  string `json:"ubic_2fa_token,omitempty"` // MFA token, classic controller.
+}
+
+// login posts the credentials, and an optional MFA token, to the login path.
+// The returned response body is already closed, its content is returned separately.
+func (u *Unifi) login(ctx context.Context, token string) (*http.Response, []byte, error) {
+	start := time.Now()
+	login := loginRequest{Username: u.User, Password: u.Pass}
+
+	if token != "" && u.new {
+		login.Token = token
+	} else if token != "" {
+		login.Ubic2FA = token
+	}
+
+	params, err := json.Marshal(&login)
+	if err != nil {
+		return nil, nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	// magic login.
+	req, err := u.UniReq(APILoginPath, string(params))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := u.Do(req.WithContext(ctx))
+	if err != nil {
+		err = fmt.Errorf("making request: %w", err)
+		u.logRequest(ctx, start, req.Method, req.URL.Path, 0, 0, err)
+
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = fmt.Errorf("reading response: %w", err)
+	}
+
+	u.logRequest(ctx, start, req.Method, req.URL.Path, resp.StatusCode, len(body), err)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, body, nil
+}
+
+// reAuth logs in again after a request was rejected because the session expired.
+// seen is the session counter observed before the rejected request was sent.
+// If another caller already logged in since then, this returns without logging in.
+func (u *Unifi) reAuth(ctx context.Context, seen uint64) error {
+	u.authMu.Lock()
+	defer u.authMu.Unlock()
+
+	if u.session.Load() != seen {
+		return nil
+	}
+
+	u.DebugLog("Session expired, logging in again as %s", u.User)
+
+	return u.LoginCtx(ctx)
+}
+
 // Logout closes the current session.
 func (u *Unifi) Logout() error {
+	return u.LogoutCtx(context.Background())
+}
+
+// LogoutCtx closes the current session using the provided context.
+func (u *Unifi) LogoutCtx(ctx context.Context) error {
 	// a post is needed for logout
-	_, err := u.PostJSON(APILogoutPath)
+	_, err := u.PostJSONCtx(ctx, APILogoutPath)
 
 	return err
 }
@@ -156,16 +243,9 @@ func (u *Unifi) Logout() error {
 // changed and broke this library. This function runs when `NewUnifi()` is called to
 // check if this is a newer controller or not. If it is, we set new to true.
 // Setting new to true makes the path() method return different (new) paths.
-func (u *Unifi) checkNewStyleAPI() error {
-	var (
-		ctx    = context.Background()
-		cancel func()
-	)
-
-	if u.Config.Timeout != 0 {
-		ctx, cancel = context.WithTimeout(ctx, u.Config.Timeout)
-		defer cancel()
-	}
+func (u *Unifi) checkNewStyleAPI(ctx context.Context) error {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
 
 	u.DebugLog("Requesting %s/ to determine API paths", u.URL)
 
@@ -180,9 +260,7 @@ func (u *Unifi) checkNewStyleAPI() error {
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: !u.VerifySSL}, // nolint: gosec
-		},
+		Transport: u.transport,
 	}
 
 	resp, err := client.Do(req)
@@ -206,11 +284,16 @@ func (u *Unifi) checkNewStyleAPI() error {
 // GetServerData sets the controller's version and UUID. Only call this if you
 // previously called Login and suspect the controller version has changed.
 func (u *Unifi) GetServerData() (*ServerStatus, error) {
+	return u.GetServerDataCtx(context.Background())
+}
+
+// GetServerDataCtx is the same as GetServerData, but uses the provided context.
+func (u *Unifi) GetServerDataCtx(ctx context.Context) (*ServerStatus, error) {
 	var response struct {
 		Data ServerStatus `json:"meta"`
 	}
 
-	err := u.GetData(APIStatusPath, &response)
+	err := u.GetDataCtx(ctx, APIStatusPath, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -222,45 +305,64 @@ func (u *Unifi) GetServerData() (*ServerStatus, error) {
 
 // GetData makes a unifi request and unmarshals the response into a provided pointer.
 func (u *Unifi) GetData(apiPath string, v interface{}, params ...string) error {
-	start := time.Now()
+	return u.GetDataCtx(context.Background(), apiPath, v, params...)
+}
 
-	body, err := u.GetJSON(apiPath, params...)
+// GetDataCtx makes a unifi request bound to a context and unmarshals the
+// response into a provided pointer.
+func (u *Unifi) GetDataCtx(ctx context.Context, apiPath string, v interface{}, params ...string) error {
+	body, err := u.GetRawCtx(ctx, apiPath, params...)
 	if err != nil {
 		return err
 	}
 
-	u.DebugLog("Requested %s: elapsed %v, returned %d bytes",
-		u.URL+u.path(apiPath), time.Since(start).Round(time.Millisecond), len(body))
-
 	return json.Unmarshal(body, v)
 }
 
-// GetData makes a unifi request and unmarshals the response into a provided pointer.
+// GetRaw makes a unifi request and returns the raw response body.
 func (u *Unifi) GetRaw(apiPath string, params ...string) ([]byte, error) {
-	start := time.Now()
+	return u.GetRawCtx(context.Background(), apiPath, params...)
+}
 
-	body, err := u.GetJSON(apiPath, params...)
+// GetRawCtx makes a unifi request bound to a context and returns the raw response body.
+// The request is a POST when params are provided, like GetJSON.
+func (u *Unifi) GetRawCtx(ctx context.Context, apiPath string, params ...string) ([]byte, error) {
+	req, err := u.UniReq(apiPath, strings.Join(params, " "))
 	if err != nil {
 		return nil, err
 	}
 
-	u.DebugLog("Requested %s: elapsed %v, returned %d bytes",
-		u.URL+u.path(apiPath), time.Since(start).Round(time.Millisecond), len(body))
-
-	return body, nil
+	return u.do(ctx, req)
 }
 
 // PutData makes a unifi request and unmarshals the response into a provided pointer.
 func (u *Unifi) PutData(apiPath string, v interface{}, params ...string) error {
-	start := time.Now()
+	return u.PutDataCtx(context.Background(), apiPath, v, params...)
+}
 
-	body, err := u.PutJSON(apiPath, params...)
+// PutDataCtx makes a unifi PUT request bound to a context and unmarshals the
+// response into a provided pointer.
+func (u *Unifi) PutDataCtx(ctx context.Context, apiPath string, v interface{}, params ...string) error {
+	body, err := u.PutJSONCtx(ctx, apiPath, params...)
 	if err != nil {
 		return err
 	}
 
-	u.DebugLog("Requested %s: elapsed %v, returned %d bytes",
-		u.URL+u.path(apiPath), time.Since(start).Round(time.Millisecond), len(body))
+	return json.Unmarshal(body, v)
+}
+
+// PatchData makes a unifi PATCH request and unmarshals the response into a provided pointer.
+func (u *Unifi) PatchData(apiPath string, v interface{}, params ...string) error {
+	return u.PatchDataCtx(context.Background(), apiPath, v, params...)
+}
+
+// PatchDataCtx makes a unifi PATCH request bound to a context and unmarshals the
+// response into a provided pointer.
+func (u *Unifi) PatchDataCtx(ctx context.Context, apiPath string, v interface{}, params ...string) error {
+	body, err := u.PatchJSONCtx(ctx, apiPath, params...)
+	if err != nil {
+		return err
+	}
 
 	return json.Unmarshal(body, v)
 }
@@ -323,76 +425,329 @@ func (u *Unifi) UniReqPost(apiPath string, params string) (*http.Request, error)
 	return req, nil
 }
 
+// UniReqPatch is the Patch call equivalent to UniReq.
+func (u *Unifi) UniReqPatch(apiPath string, params string) (*http.Request, error) {
+	if params == "" {
+		return nil, ErrNoParams
+	}
+
+	apiPath = u.path(apiPath)
+
+	req, err := http.NewRequest(http.MethodPatch, u.URL+apiPath, bytes.NewBufferString(params)) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	u.setHeaders(req, params)
+
+	return req, nil
+}
+
+// UniReqDelete is the Delete call equivalent to UniReq.
+func (u *Unifi) UniReqDelete(apiPath string) (*http.Request, error) {
+	apiPath = u.path(apiPath)
+
+	req, err := http.NewRequest(http.MethodDelete, u.URL+apiPath, nil) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	u.setHeaders(req, "")
+
+	return req, nil
+}
+
 // GetJSON returns the raw JSON from a path. This is useful for debugging.
 func (u *Unifi) GetJSON(apiPath string, params ...string) ([]byte, error) {
+	return u.GetJSONCtx(context.Background(), apiPath, params...)
+}
+
+// GetJSONCtx returns the raw JSON from a path using the provided context.
+func (u *Unifi) GetJSONCtx(ctx context.Context, apiPath string, params ...string) ([]byte, error) {
 	req, err := u.UniReq(apiPath, strings.Join(params, " "))
 	if err != nil {
 		return []byte{}, err
 	}
 
-	return u.do(req)
+	return u.do(ctx, req)
 }
 
 // PutJSON uses a PUT call and returns the raw JSON in the same way as GetData
 // Use this if you want to change data via the REST API.
 func (u *Unifi) PutJSON(apiPath string, params ...string) ([]byte, error) {
+	return u.PutJSONCtx(context.Background(), apiPath, params...)
+}
+
+// PutJSONCtx is the same as PutJSON, but uses the provided context.
+func (u *Unifi) PutJSONCtx(ctx context.Context, apiPath string, params ...string) ([]byte, error) {
 	req, err := u.UniReqPut(apiPath, strings.Join(params, " "))
 	if err != nil {
 		return []byte{}, err
 	}
 
-	return u.do(req)
+	return u.do(ctx, req)
 }
 
 // PostJSON uses a POST call and returns the raw JSON in the same way as GetData
 // Use this if you want to change data via the REST API.
 func (u *Unifi) PostJSON(apiPath string, params ...string) ([]byte, error) {
+	return u.PostJSONCtx(context.Background(), apiPath, params...)
+}
+
+// PostJSONCtx is the same as PostJSON, but uses the provided context.
+func (u *Unifi) PostJSONCtx(ctx context.Context, apiPath string, params ...string) ([]byte, error) {
 	req, err := u.UniReqPost(apiPath, strings.Join(params, " "))
 	if err != nil {
 		return []byte{}, err
 	}
 
-	return u.do(req)
+	return u.do(ctx, req)
 }
 
-func (u *Unifi) do(req *http.Request) ([]byte, error) {
-	var (
-		cancel func()
-		ctx    = context.Background()
-	)
+// PatchJSON uses a PATCH call and returns the raw JSON in the same way as GetData.
+// Use this to change some fields of an object via the Protect API.
+func (u *Unifi) PatchJSON(apiPath string, params ...string) ([]byte, error) {
+	return u.PatchJSONCtx(context.Background(), apiPath, params...)
+}
+
+// PatchJSONCtx is the same as PatchJSON, but uses the provided context.
+func (u *Unifi) PatchJSONCtx(ctx context.Context, apiPath string, params ...string) ([]byte, error) {
+	req, err := u.UniReqPatch(apiPath, strings.Join(params, " "))
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return u.do(ctx, req)
+}
+
+// DeleteJSON uses a DELETE call and returns the raw JSON in the same way as GetData.
+// Use this to remove an object via the REST API.
+func (u *Unifi) DeleteJSON(apiPath string) ([]byte, error) {
+	return u.DeleteJSONCtx(context.Background(), apiPath)
+}
+
+// DeleteJSONCtx is the same as DeleteJSON, but uses the provided context.
+func (u *Unifi) DeleteJSONCtx(ctx context.Context, apiPath string) ([]byte, error) {
+	req, err := u.UniReqDelete(apiPath)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return u.do(ctx, req)
+}
+
+// withTimeout applies Config.Timeout to a context, if a timeout is configured.
+// The returned cancel func must always be called.
+func (u *Unifi) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if u.Config.Timeout == 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, u.Config.Timeout)
+}
+
+func (u *Unifi) do(ctx context.Context, req *http.Request) ([]byte, error) {
+	return doRetry(ctx, u, req, u.doOnce)
+}
+
+// sender sends a single request and returns the response and status code.
+// doOnce reads the whole response body; streamOnce leaves it to the caller.
+type sender[T any] func(ctx context.Context, req *http.Request) (T, int, error)
+
+// doRetry sends a request with send, and retries it according to Config.Retry.
+func doRetry[T any](ctx context.Context, u *Unifi, req *http.Request, send sender[T]) (T, error) {
+	for attempt := 1; ; attempt++ {
+		body, status, err := doAuth(ctx, u, req, send)
+
+		delay, retry := u.Retry.retry(ctx, req.Method, attempt, status, err)
+		if !retry {
+			return body, err
+		}
+
+		u.DebugLog("Retrying %s %s in %v (attempt %d of %d): %v",
+			req.Method, req.URL, delay.Round(time.Millisecond), attempt+1, u.Retry.MaxAttempts, err)
+
+		if err := sleepCtx(ctx, delay); err != nil {
+			return body, fmt.Errorf("waiting to retry: %w", err)
+		}
+
+		if req, err = u.replay(ctx, req); err != nil {
+			return body, err
+		}
+	}
+}
+
+// doAuth sends a request, and if the session expired, logs in and replays it once.
+func doAuth[T any](ctx context.Context, u *Unifi, req *http.Request, send sender[T]) (T, int, error) {
+	session := u.session.Load()
+
+	body, status, err := send(ctx, req)
+	if !u.ReAuth || u.APIKey != "" || (status != http.StatusUnauthorized && status != http.StatusForbidden) ||
+		req.URL.Path == u.path(APILoginPath) {
+		return body, status, err
+	}
 
-	if u.Config.Timeout != 0 {
-		ctx, cancel = context.WithTimeout(ctx, u.Config.Timeout)
-		defer cancel()
+	if err := u.reAuth(ctx, session); err != nil {
+		return body, status, fmt.Errorf("re-authenticating: %w", err)
 	}
 
+	if req, err = u.replay(ctx, req); err != nil {
+		return body, status, err
+	}
+
+	return send(ctx, req)
+}
+
+// doOnce sends a single request and returns the body and status code.
+func (u *Unifi) doOnce(ctx context.Context, req *http.Request) (body []byte, status int, err error) {
+	start := time.Now()
+	defer func(ctx context.Context) {
+		u.observe(ctx, start, req.Method, req.URL.Path, status, len(body), err)
+		u.logRequest(ctx, start, req.Method, req.URL.Path, status, len(body), err)
+	}(ctx)
+
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
 	resp, err := u.Do(req.WithContext(ctx))
 	if err != nil {
-		return []byte{}, fmt.Errorf("making request: %w", err)
+		return []byte{}, 0, fmt.Errorf("making request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if u.transport == nil {
+		u.saveCSRF(resp.Header) // authTransport saves it otherwise.
+	}
+
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return body, fmt.Errorf("reading response: %w", err)
+		return body, resp.StatusCode, fmt.Errorf("reading response: %w", err)
 	}
 
-	// Save the returned CSRF header.
-	if csrf := resp.Header.Get("x-csrf-token"); csrf != "" {
-		u.csrf = resp.Header.Get("x-csrf-token")
+	return body, resp.StatusCode, apiError(req, resp.StatusCode, body)
+}
+
+// getStreamCtx makes a unifi GET request and returns the response body without reading it.
+// The caller must close the body. Config.Timeout is not applied, so long downloads are
+// not cut off; use the context to bound them.
+func (u *Unifi) getStreamCtx(ctx context.Context, apiPath string) (io.ReadCloser, error) {
+	req, err := u.UniReq(apiPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return doRetry(ctx, u, req, u.streamOnce)
+}
+
+// streamOnce sends a single request and returns the response body unread if the status is 200.
+// The request is logged and observed when the body is closed.
+func (u *Unifi) streamOnce(ctx context.Context, req *http.Request) (io.ReadCloser, int, error) {
+	start := time.Now()
+	client := *u.Client
+	client.Timeout = 0
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		err = fmt.Errorf("making request: %w", err)
+		u.observe(ctx, start, req.Method, req.URL.Path, 0, 0, err)
+		u.logRequest(ctx, start, req.Method, req.URL.Path, 0, 0, err)
+
+		return nil, 0, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%s: %s: %w", req.URL, resp.Status, ErrInvalidStatusCode)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		err = apiError(req, resp.StatusCode, body)
+		u.observe(ctx, start, req.Method, req.URL.Path, resp.StatusCode, len(body), err)
+		u.logRequest(ctx, start, req.Method, req.URL.Path, resp.StatusCode, len(body), err)
+
+		return nil, resp.StatusCode, err
+	}
+
+	return &streamBody{ReadCloser: resp.Body, done: func(size int, err error) {
+		u.observe(ctx, start, req.Method, req.URL.Path, resp.StatusCode, size, err)
+		u.logRequest(ctx, start, req.Method, req.URL.Path, resp.StatusCode, size, err)
+	}}, resp.StatusCode, nil
+}
+
+// streamBody counts the bytes read from a response body, and calls done when it's closed.
+type streamBody struct {
+	io.ReadCloser
+	size int
+	err  error
+	done func(size int, err error)
+}
+
+func (s *streamBody) Read(p []byte) (int, error) {
+	n, err := s.ReadCloser.Read(p)
+	s.size += n
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.err = fmt.Errorf("reading response: %w", err)
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+func (s *streamBody) Close() error {
+	if s.done != nil {
+		s.done(s.size, s.err)
+		s.done = nil
 	}
 
-	return body, err
+	return s.ReadCloser.Close() //nolint:wrapcheck
+}
+
+// replay returns a copy of a request that can be sent again after logging in or
+// after a transient failure. The body is rewound, and the stale cookies and CSRF token are replaced.
+func (u *Unifi) replay(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("rewinding request body: %w", err)
+		}
+
+		clone.Body = body
+	}
+
+	// The http.Client adds cookies from the jar to every request it sends.
+	// The new CSRF token is added by authTransport.
+	clone.Header.Del("Cookie")
+
+	return clone, nil
 }
 
+// saveCSRF stores the CSRF token from a response header, if one was returned.
+func (u *Unifi) saveCSRF(header http.Header) {
+	if csrf := header.Get("x-csrf-token"); csrf != "" {
+		u.csrfMu.Lock()
+		u.csrf = csrf
+		u.csrfMu.Unlock()
+	}
+}
+
+func (u *Unifi) getCSRF() string {
+	u.csrfMu.RLock()
+	defer u.csrfMu.RUnlock()
+
+	return u.csrf
+}
+
+// setHeaders adds the content headers. The API key and CSRF headers are added
+// to every request by authTransport. A Unifi that was not created by NewUnifi
+// has no authTransport, so they are added here instead.
 func (u *Unifi) setHeaders(req *http.Request, params string) {
-	// Add the saved CSRF header.
-	req.Header.Set("X-CSRF-Token", u.csrf)
+	if u.transport == nil && u.APIKey != "" {
+		req.Header.Set(APIKeyHeader, u.APIKey)
+	} else if u.transport == nil {
+		req.Header.Set("X-CSRF-Token", u.getCSRF())
+	}
+
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json; charset=utf-8")
 
@@ -404,12 +759,18 @@ func (u *Unifi) setHeaders(req *http.Request, params string) {
 	}
 }
 
+// GetCameras returns all of the Protect cameras known to the NVR.
 func (u *Unifi) GetCameras() ([]*Camera, error) {
+	return u.GetCamerasCtx(context.Background())
+}
+
+// GetCamerasCtx returns all of the Protect cameras using the provided context.
+func (u *Unifi) GetCamerasCtx(ctx context.Context) ([]*Camera, error) {
 	start := time.Now()
 
 	var data []*Camera
 
-	err := u.GetData("/api/cameras", &data)
+	err := u.GetDataCtx(ctx, "/api/cameras", &data)
 	if err != nil {
 		return nil, err
 	}
@@ -420,8 +781,14 @@ func (u *Unifi) GetCameras() ([]*Camera, error) {
 	return data, nil
 }
 
+// GetCameraByID returns the camera with the provided ID.
 func (u *Unifi) GetCameraByID(value string) (*Camera, error) {
-	cameras, err := u.GetCameras()
+	return u.GetCameraByIDCtx(context.Background(), value)
+}
+
+// GetCameraByIDCtx returns the camera with the provided ID using the provided context.
+func (u *Unifi) GetCameraByIDCtx(ctx context.Context, value string) (*Camera, error) {
+	cameras, err := u.GetCamerasCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -437,7 +804,12 @@ func (u *Unifi) GetCameraByID(value string) (*Camera, error) {
 
 // Acutally retreived by "displayName", in testing "name" was not always present (null value) while "displayName" always was. If it was present they were always identitcal.
 func (u *Unifi) GetCameraByName(value string) (*Camera, error) {
-	cameras, err := u.GetCameras()
+	return u.GetCameraByNameCtx(context.Background(), value)
+}
+
+// GetCameraByNameCtx returns the camera with the provided name using the provided context.
+func (u *Unifi) GetCameraByNameCtx(ctx context.Context, value string) (*Camera, error) {
+	cameras, err := u.GetCamerasCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -450,67 +822,3 @@ func (u *Unifi) GetCameraByName(value string) (*Camera, error) {
 
 	return nil, fmt.Errorf("Camera with id \"%s\" not found", value)
 }
-
-// Prepare and download a clip from the specified camera for the time window. In testing, the prepare API can be overloaded
-// and will start throwing 500 errors. Two other major errors yet to be understood exist. The first relates to the length of
-// the clip, sometimes the clip is either shorter or longer than the specified time. The second has to do with requesting
-// clips from time periods too close to "now", Unifi will return 500 if this is the case.
-func (u *Unifi) GetClipBytes(cameraID string, start, end time.Time) ([]byte, error) {
-	var prepValues = url.Values{}
-
-	prepValues.Set("camera", cameraID)
-	prepValues.Set("start", strconv.FormatInt(start.UnixMilli(), 10))
-	prepValues.Set("end", strconv.FormatInt(end.UnixMilli(), 10))
-	prepValues.Set("channel", "0")
-	prepValues.Set("lens", "0")
-	prepValues.Set("type", "rotating")
-	prepValues.Set("filename", fmt.Sprintf("%s_%s-%s.mp4", prepValues.Get("camera"), prepValues.Get("start"), prepValues.Get("end")))
-
-	// Prepare Clip Download
-	var responsePrep interface{}
-
-	prepClipURL := "/api/video/prepare?" + prepValues.Encode()
-
-	err := u.GetData(prepClipURL, &responsePrep)
-	if err != nil {
-		return nil, err
-	}
-
-	// Download Clip
-	var responseDownload []byte
-
-	var downloadValues = url.Values{}
-
-	downloadValues.Set("camera", prepValues.Get("camera"))
-	downloadValues.Set("filename", prepValues.Get("filename"))
-
-	downloadClipURL := "/api/video/download?" + downloadValues.Encode()
-
-	responseDownload, err = u.GetRaw(downloadClipURL)
-	if err != nil {
-		return nil, err
-	}
-
-	return responseDownload, nil
-}
-
-// Prepare and download a clip from the specified camera for the time window then return a temp file where it's located.
-// See GetClipBytes for more.
-func (u *Unifi) DownloadClip(cameraID string, start, end time.Time) (*os.File, error) {
-	clipBytes, err := u.GetClipBytes(cameraID, start, end)
-	if err != nil {
-		return nil, err
-	}
-
-	f, err := os.CreateTemp("", cameraID)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = f.Write(clipBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	return f, nil
-}
@@ -1,9 +1,15 @@
 package unifi // nolint: testpackage
 
 import (
+	"context"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -93,3 +99,205 @@ func TestUniReqPut(t *testing.T) {
 a.EqualValues(`{"username": "user1","password": "pass2"}`, string(post_params),
 	"user/pass json parameters improperly encoded")
 */
+
+func TestGetDataCtxCanceled(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	u := &Unifi{Client: srv.Client(), Config: &Config{URL: srv.URL, DebugLog: discardLogs}}
+	err := u.GetDataCtx(ctx, APISiteList, &struct{}{})
+	a.ErrorIs(err, context.DeadlineExceeded, "a canceled context must abort the request")
+}
+
+func TestDevMgrCtxCanceled(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body) // a disconnect is not noticed until the body is read.
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	u := &Unifi{Client: srv.Client(), Config: &Config{URL: srv.URL, DebugLog: discardLogs}}
+	uap := &UAP{site: &Site{controller: u, Name: "default"}, Mac: "00:11:22:33:44:55"}
+	a.ErrorIs(uap.RestartCtx(ctx), context.DeadlineExceeded, "device commands must use the context")
+}
+
+func TestCSRFWithoutNewUnifi(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	tokens := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens <- r.Header.Get("X-CSRF-Token")
+		w.Header().Set("X-CSRF-Token", "csrf123")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	u := &Unifi{Client: srv.Client(), Config: &Config{URL: srv.URL, DebugLog: discardLogs}}
+	a.NoError(u.GetData(APISiteList, &struct{}{}))
+	a.NoError(u.GetData(APISiteList, &struct{}{}))
+	a.Equal("", <-tokens)
+	a.Equal("csrf123", <-tokens, "the returned CSRF token must be sent without an authTransport")
+}
+
+func TestReAuth(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var logins atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == APILoginPath {
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "unifises", Value: "fresh"})
+			w.Header().Set("X-CSRF-Token", "token2")
+
+			return
+		}
+
+		if c, err := r.Cookie("unifises"); err != nil || c.Value != "fresh" || r.Header.Get("X-CSRF-Token") != "token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	u := newUnifi(&Config{URL: srv.URL, ReAuth: true}, jar)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			a.NoError(u.GetData(APISiteList, &struct{}{}), "the request must be replayed after logging in")
+		}()
+	}
+
+	wg.Wait()
+	a.EqualValues(1, logins.Load(), "concurrent callers must only log in once")
+
+	jar, _ = cookiejar.New(nil)
+	u = newUnifi(&Config{URL: srv.URL}, jar)
+	a.ErrorIs(u.GetData(APISiteList, &struct{}{}), ErrInvalidStatusCode, "without ReAuth the 401 must be returned")
+	a.EqualValues(1, logins.Load(), "without ReAuth there must be no login")
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			return // new style API.
+		case r.URL.Path == APILoginPathNew:
+			t.Error("login must not be called when an API key is provided")
+		case r.Header.Get(APIKeyHeader) != "key123" || r.Header.Get("X-CSRF-Token") != "":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = w.Write([]byte(`{"data":[{"name":"default"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	u, err := NewUnifi(&Config{URL: srv.URL, APIKey: "key123"})
+	a.NoError(err)
+
+	sites, err := u.GetSites()
+	a.NoError(err, "requests must be authenticated with the API key")
+	a.Len(sites, 1)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == APILoginPathNew {
+			w.Header().Set("X-CSRF-Token", "csrf123")
+		}
+
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				order = append(order, name+" "+req.URL.Path+" "+req.Header.Get("X-CSRF-Token"))
+				mu.Unlock()
+
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	base := &http.Transport{}
+	u, err := NewUnifi(&Config{
+		URL:          srv.URL,
+		User:         "user",
+		Pass:         "pass",
+		RoundTripper: base,
+		Middleware:   []Middleware{record("first"), record("second")},
+	})
+	a.NoError(err)
+	a.False(base.TLSClientConfig != nil && base.TLSClientConfig.InsecureSkipVerify,
+		"the provided transport must not be modified")
+
+	_, err = u.GetSites()
+	a.NoError(err)
+	a.Equal([]string{
+		"first / ", "second / ",
+		"first " + APILoginPathNew + " ", "second " + APILoginPathNew + " ",
+		"first " + APIPrefixNew + APISiteList + " csrf123", "second " + APIPrefixNew + APISiteList + " csrf123",
+	}, order, "middleware must run in order, beneath the auth headers")
+}
+
+func TestPinningRequiresTransport(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	sent := false
+	base := RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		sent = true
+		return nil, http.ErrNotSupported
+	})
+
+	_, err := NewUnifi(&Config{URL: "https://127.0.0.1", APIKey: "key", SSLCert: [][]byte{{}}, RoundTripper: base})
+	a.ErrorIs(err, ErrPinningUnsupported)
+
+	u := newUnifi(&Config{URL: "https://127.0.0.1", APIKey: "key", RoundTripper: base}, nil)
+	u.fingerprints = fingerprints{"abc"}
+	u.SSLCert = [][]byte{{}}
+	u.Client.Transport, u.transport = u.newTransport()
+
+	_, err = u.GetSites()
+	a.ErrorIs(err, ErrPinningUnsupported)
+	a.False(sent, "nothing may be sent without checking the certificate")
+}
@@ -1,6 +1,7 @@
 package unifi
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -8,37 +9,42 @@ import (
 // GetUsers returns a response full of clients that connected to the UDM within the provided amount of time
 // using the insight historical connection data set.
 func (u *Unifi) GetUsers(sites []*Site, hours int) ([]*User, error) {
-	data := make([]*User, 0)
-
-	for _, site := range sites {
-		var (
-			response struct {
-				Data []*User `json:"data"`
-			}
-			params = fmt.Sprintf(`{ "type": "all:", "conn": "all", "within":%d }`, hours)
-		)
+	return u.GetUsersCtx(context.Background(), sites, hours)
+}
 
-		u.DebugLog("Polling Controller, retrieving UniFi Users, site %s ", site.SiteName)
+// GetUsersCtx is the same as GetUsers, but uses the provided context.
+func (u *Unifi) GetUsersCtx(ctx context.Context, sites []*Site, hours int) ([]*User, error) {
+	return eachSite(ctx, u, sites, func(ctx context.Context, site *Site) ([]*User, error) {
+		return u.getUsersSite(ctx, site, hours)
+	})
+}
 
-		clientPath := fmt.Sprintf(APIAllUserPath, site.Name)
-		if err := u.GetData(clientPath, &response, params); err != nil {
-			return nil, err
+func (u *Unifi) getUsersSite(ctx context.Context, site *Site, hours int) ([]*User, error) {
+	var (
+		response struct {
+			Data []*User `json:"data"`
 		}
+		params = fmt.Sprintf(`{ "type": "all:", "conn": "all", "within":%d }`, hours)
+	)
 
-		for i, d := range response.Data {
-			// Add special SourceName value.
-			response.Data[i].SourceName = u.URL
-			// Add the special "Site Name" to each client. This becomes a Grafana filter somewhere.
-			response.Data[i].SiteName = site.SiteName
-			// Fix name and hostname fields. Sometimes one or the other is blank.
-			response.Data[i].Hostname = strings.TrimSpace(pick(d.Hostname, d.Name, d.Mac))
-			response.Data[i].Name = strings.TrimSpace(pick(d.Name, d.Hostname))
-		}
+	u.DebugLog("Polling Controller, retrieving UniFi Users, site %s ", site.SiteName)
+
+	clientPath := fmt.Sprintf(APIAllUserPath, site.Name)
+	if err := u.GetDataCtx(ctx, clientPath, &response, params); err != nil {
+		return nil, err
+	}
 
-		data = append(data, response.Data...)
+	for i, d := range response.Data {
+		// Add special SourceName value.
+		response.Data[i].SourceName = u.URL
+		// Add the special "Site Name" to each client. This becomes a Grafana filter somewhere.
+		response.Data[i].SiteName = site.SiteName
+		// Fix name and hostname fields. Sometimes one or the other is blank.
+		response.Data[i].Hostname = strings.TrimSpace(pick(d.Hostname, d.Name, d.Mac))
+		response.Data[i].Name = strings.TrimSpace(pick(d.Name, d.Hostname))
 	}
 
-	return data, nil
+	return response.Data, nil
 }
 
 // User defines the metadata available for previously connected clients.
@@ -0,0 +1,297 @@
+package unifi
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// These are returned when a websocket can't be dialed the way other requests are sent.
+var (
+	ErrWebsocketTransport = fmt.Errorf("websockets require an *http.Transport RoundTripper")
+	ErrWebsocketProxy     = fmt.Errorf("unsupported websocket proxy scheme")
+)
+
+// dialWebsocket opens a websocket to an API path on the controller. The path gets the
+// same prefix as other requests, and the connection uses the same TLS settings and
+// credentials: the API key, or the session cookies and CSRF token. The connection is
+// made with the Proxy and DialContext of Config.RoundTripper, like other requests.
+// Config.Middleware is not used: after the upgrade, a websocket is not HTTP requests.
+func (u *Unifi) dialWebsocket(ctx context.Context, apiPath string) (*websocket.Conn, error) {
+	httpURL, err := url.Parse(u.URL + u.path(apiPath))
+	if err != nil {
+		return nil, fmt.Errorf("parsing websocket url: %w", err)
+	}
+
+	wsURL := *httpURL
+	if wsURL.Scheme = "ws"; httpURL.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+
+	config, err := websocket.NewConfig(wsURL.String(), u.URL)
+	if err != nil {
+		return nil, fmt.Errorf("creating websocket config: %w", err)
+	}
+
+	transport, err := u.websocketTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	config.TlsConfig = u.tlsConfig(transport.TLSClientConfig)
+
+	if u.APIKey != "" {
+		config.Header.Set(APIKeyHeader, u.APIKey)
+	} else {
+		if csrf := u.getCSRF(); csrf != "" {
+			config.Header.Set("X-CSRF-Token", csrf)
+		}
+
+		if u.Client.Jar != nil {
+			for _, cookie := range u.Client.Jar.Cookies(httpURL) {
+				config.Header.Add("Cookie", cookie.String())
+			}
+		}
+	}
+
+	conn, err := dialTransport(ctx, transport, httpURL, config.TlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("dialing websocket: %w", err)
+	}
+
+	// The websocket handshake does not take a context; closing the connection ends it.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+
+	wsConn, err := websocket.NewClient(config, conn)
+	if !stop() {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dialing websocket: %w", err)
+	}
+
+	u.DebugLog("Connected websocket %s", wsURL.String())
+
+	return wsConn, nil
+}
+
+// websocketTransport returns the transport websockets are dialed with. Only an
+// *http.Transport has a Proxy and DialContext to dial with.
+func (u *Unifi) websocketTransport() (*http.Transport, error) {
+	switch base := u.RoundTripper.(type) {
+	case nil:
+		return &http.Transport{}, nil
+	case *http.Transport:
+		return base, nil
+	default:
+		return nil, fmt.Errorf("%w, not %T", ErrWebsocketTransport, u.RoundTripper)
+	}
+}
+
+// dialTransport opens a connection to the host in target the way transport would for
+// a request: through its proxy, with its DialContext, and with TLS for https.
+func dialTransport(ctx context.Context, transport *http.Transport, target *url.URL, config *tls.Config) (net.Conn, error) {
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	addr := canonicalAddr(target)
+
+	var proxy *url.URL
+
+	if transport.Proxy != nil {
+		var err error
+
+		if proxy, err = transport.Proxy(&http.Request{Method: http.MethodGet, URL: target, Header: http.Header{}}); err != nil {
+			return nil, fmt.Errorf("getting proxy: %w", err)
+		}
+	}
+
+	if proxy == nil && target.Scheme == "https" && transport.DialTLSContext != nil {
+		return transport.DialTLSContext(ctx, "tcp", addr) //nolint:wrapcheck
+	}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if proxy == nil {
+		conn, err = dial(ctx, "tcp", addr)
+	} else {
+		conn, err = dialProxy(ctx, dial, proxy, addr, transport.TLSClientConfig)
+	}
+
+	if err != nil || target.Scheme != "https" {
+		return conn, err
+	}
+
+	config = config.Clone()
+	if config.ServerName == "" {
+		config.ServerName = target.Hostname()
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("tls handshake: %w", err)
+	}
+
+	return tlsConn, nil
+}
+
+// dialProxy opens a tunnel to addr through an http or https proxy with a CONNECT request.
+func dialProxy(
+	ctx context.Context, dial func(context.Context, string, string) (net.Conn, error),
+	proxy *url.URL, addr string, config *tls.Config,
+) (net.Conn, error) {
+	if proxy.Scheme != "http" && proxy.Scheme != "https" {
+		return nil, fmt.Errorf("%w: %s", ErrWebsocketProxy, proxy.Scheme)
+	}
+
+	conn, err := dial(ctx, "tcp", canonicalAddr(proxy))
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy.Scheme == "https" {
+		config = config.Clone()
+		if config == nil {
+			config = &tls.Config{} //nolint:gosec
+		}
+
+		config.ServerName = proxy.Hostname()
+		tlsConn := tls.Client(conn, config)
+
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy tls handshake: %w", err)
+		}
+
+		conn = tlsConn
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		connect.SetBasicAuth(proxy.User.Username(), password)
+		connect.Header.Set("Proxy-Authorization", connect.Header.Get("Authorization"))
+		connect.Header.Del("Authorization")
+	}
+
+	if err := connect.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("writing proxy connect: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connect)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("reading proxy connect: %w", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("%w: proxy connect: %s", ErrInvalidStatusCode, resp.Status)
+	}
+
+	return conn, nil
+}
+
+// canonicalAddr returns the host:port of a URL, with the default port for its scheme.
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" || u.Scheme == "wss" {
+			port = "443"
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// readWebsocket passes every message received on a websocket to handle until the
+// connection fails or the context is done. The connection is always closed.
+func (u *Unifi) readWebsocket(ctx context.Context, conn *websocket.Conn, handle func([]byte)) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		conn.Close()
+	}()
+
+	for {
+		var msg []byte
+
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err() //nolint:wrapcheck
+			}
+
+			return fmt.Errorf("reading websocket: %w", err)
+		}
+
+		handle(msg)
+	}
+}
+
+// keepWebsocket connects a websocket and passes its messages to handle until the
+// context is done. Before every connection, connect is called to get the API path
+// to dial; a failed connection is retried with the Config.Retry backoff, forever.
+func (u *Unifi) keepWebsocket(
+	ctx context.Context, connect func(ctx context.Context) (string, error), handle func([]byte),
+) {
+	backoff := u.Retry
+	if backoff == nil {
+		backoff = &RetryPolicy{}
+	}
+
+	for attempt := 1; ctx.Err() == nil; attempt++ {
+		apiPath, err := connect(ctx)
+		if err == nil {
+			var conn *websocket.Conn
+
+			if conn, err = u.dialWebsocket(ctx, apiPath); err == nil {
+				attempt = 1
+				err = u.readWebsocket(ctx, conn, handle)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		delay := backoff.backoff(attempt)
+		u.logger().Error("websocket disconnected, reconnecting", "path", pathTemplate(apiPath), "delay", delay, "error", err)
+
+		if sleepCtx(ctx, delay) != nil {
+			return
+		}
+	}
+}
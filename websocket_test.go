@@ -0,0 +1,83 @@
+package unifi // nolint: testpackage
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestDialWebsocketTransport(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	srv := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		_ = websocket.Message.Send(conn, conn.Request().Header.Get(APIKeyHeader))
+	}))
+	t.Cleanup(srv.Close)
+
+	var connects, dials atomic.Int32
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		connects.Add(1)
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+		go func() { _, _ = io.Copy(upstream, conn) }()
+
+		_, _ = io.Copy(conn, upstream)
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	transport := &http.Transport{
+		Proxy: http.ProxyURL(proxyURL),
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials.Add(1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+
+	u := newUnifi(&Config{URL: srv.URL, APIKey: "key", RoundTripper: transport}, nil)
+
+	conn, err := u.dialWebsocket(context.Background(), "/ws")
+	require.NoError(t, err)
+
+	var msg string
+
+	require.NoError(t, websocket.Message.Receive(conn, &msg))
+	conn.Close()
+	a.Equal("key", msg)
+	a.EqualValues(1, connects.Load(), "the websocket must be dialed through the proxy.")
+	a.EqualValues(1, dials.Load(), "the transport's DialContext must be used.")
+
+	u = newUnifi(&Config{URL: srv.URL, APIKey: "key", RoundTripper: RoundTripperFunc(http.DefaultTransport.RoundTrip)}, nil)
+	_, err = u.dialWebsocket(context.Background(), "/ws")
+	a.ErrorIs(err, ErrWebsocketTransport)
+
+	_, err = u.SubscribeProtect(context.Background())
+	a.ErrorIs(err, ErrWebsocketTransport, "subscriptions must fail instead of retrying forever.")
+}